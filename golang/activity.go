@@ -0,0 +1,442 @@
+package main
+
+import (
+	"context"
+	"math"
+)
+
+// spend >= 2 * (num / den), rearranged to spend*den >= 2*num.
+// done in int64 so near-max int32 spend and medians can't overflow
+// (den is 1 or 2, so every product fits)
+func exceedsMedian(spend int32, num, den int64) bool {
+	return int64(spend)*den >= 2*num
+}
+
+// picks between the two activityNotifications versions.
+// counting sort scans the whole 0..200 counts array each day, the sort based
+// one sorts the d day window each day, so roughly
+//
+//	counting: n * 201      sort: n * d*log2(d)
+//
+// counting wins once d*log2(d) passes 201 (from d = 39 on), but it only works when
+// every value fits in 0..200, otherwise the sort based one is the only option
+func ActivityNotificationsAuto(expenditure []int32, d int32) int32 {
+	if useCountingSort(expenditure, d) {
+		return activityNotifications(expenditure, d)
+	}
+	return activityNotifications2(expenditure, d)
+}
+
+func useCountingSort(expenditure []int32, d int32) bool {
+	for _, v := range expenditure {
+		if v < 0 || v >= windowValues {
+			return false
+		}
+	}
+	sortCost := float64(d) * math.Log2(float64(max(d, 2)))
+	return sortCost > windowValues
+}
+
+// expenditures are 0..200, so a window is 201 counters
+const windowValues = 201
+
+// WindowCounter is the counting sort array behind activityNotifications, for
+// callers that want to slide the window themselves. values have to be in 0..200
+type WindowCounter struct {
+	counts []int // counts[v] = how many times v is in the window
+	size   int32
+}
+
+func NewWindowCounter() *WindowCounter {
+	return &WindowCounter{counts: make([]int, windowValues)}
+}
+
+func (w *WindowCounter) Add(v int32) {
+	w.counts[v]++
+	w.size++
+}
+
+// v has to be in the window
+func (w *WindowCounter) Remove(v int32) {
+	w.counts[v]--
+	w.size--
+}
+
+func (w *WindowCounter) Len() int32 {
+	return w.size
+}
+
+// walks the counts until it reaches the middle position(s), 0 for an empty window
+func (w *WindowCounter) Median() float64 {
+	num, den := w.MedianFrac()
+	return float64(num) / float64(den)
+}
+
+// the median as num/den, den is 2 when it's the average of two middles and 1 otherwise
+func (w *WindowCounter) MedianFrac() (num, den int64) {
+	target1 := (w.size + 1) / 2 // middle position, 1st of the two middles when size is even
+	target2 := w.size/2 + 1     // same as target1 when size is odd
+	first := -1
+	cum := int32(0)
+	for value, freq := range w.counts {
+		cum += int32(freq)
+		if first == -1 && cum >= target1 {
+			first = value
+		}
+		if cum >= target2 {
+			if first == value {
+				return int64(value), 1
+			}
+			return int64(first) + int64(value), 2
+		}
+	}
+	return 0, 1
+}
+
+// how many values in the window are strictly above num/den (a MedianFrac median)
+func (w *WindowCounter) CountAbove(num, den int64) int {
+	above := 0
+	for value, freq := range w.counts {
+		if int64(value)*den > num {
+			above += freq
+		}
+	}
+	return above
+}
+
+// WindowStat describes the d day trailing window in front of one day
+type WindowStat struct {
+	Sum    int64   // total spend over the window
+	Median float64 // median spend over the window
+	Alert  bool    // that day's spend >= 2 * Median
+}
+
+// one WindowStat per day that has a full window behind it (day d onwards).
+// Sum is slid along with the counts (add new, drop old) instead of re-adding the window
+func ActivityWindowStats(expenditure []int32, d int32) []WindowStat {
+	if d <= 0 || len(expenditure) <= int(d) {
+		return []WindowStat{}
+	}
+	window := NewWindowCounter()
+	sum := int64(0)
+	for i := 0; i < int(d); i++ {
+		window.Add(expenditure[i])
+		sum += int64(expenditure[i])
+	}
+
+	stats := []WindowStat{}
+	for i := int(d); i < len(expenditure); i++ {
+		median := window.Median()
+		stats = append(stats, WindowStat{
+			Sum:    sum,
+			Median: median,
+			Alert:  float64(expenditure[i]) >= 2*median,
+		})
+
+		old := expenditure[i-int(d)]
+		window.Remove(old)
+		window.Add(expenditure[i])
+		sum += int64(expenditure[i]) - int64(old)
+	}
+	return stats
+}
+
+// activityNotifications for many accounts at once, alerts[i] is for histories[i]
+func ActivityNotificationsBatch(histories [][]int32, d int32, concurrency int) []int32 {
+	alerts, _ := RunPool(context.Background(), histories, concurrency, func(_ context.Context, h []int32) (int32, error) {
+		return activityNotifications(h, d), nil
+	})
+	return alerts
+}
+
+// DayResult is one day that had a full d day window behind it
+type DayResult struct {
+	Index  int     // day index in expenditure
+	Median float64 // median of the d days before it
+	Spend  int32   // expenditure[Index]
+	Alert  bool    // Spend >= 2 * Median
+}
+
+// the activityNotifications loop with every day's median / alert kept, total is the alert count
+func ActivityNotificationsDetailed(expenditure []int32, d int32) (total int32, days []DayResult) {
+	days = []DayResult{}
+	if d <= 0 || len(expenditure) <= int(d) {
+		return 0, days
+	}
+	window := NewWindowCounter()
+	for i := 0; i < int(d); i++ {
+		window.Add(expenditure[i])
+	}
+	for i := int(d); i < len(expenditure); i++ {
+		median := window.Median()
+		day := DayResult{Index: i, Median: median, Spend: expenditure[i]}
+		day.Alert = float64(day.Spend) >= 2*median
+		if day.Alert {
+			total++
+		}
+		days = append(days, day)
+
+		window.Remove(expenditure[i-int(d)])
+		window.Add(expenditure[i])
+	}
+	return total, days
+}
+
+// indices of the days that raised an alert
+func ActivityNotificationDays(expenditure []int32, d int32) []int {
+	_, days := ActivityNotificationsDetailed(expenditure, d)
+	alerts := []int{}
+	for _, day := range days {
+		if day.Alert {
+			alerts = append(alerts, day.Index)
+		}
+	}
+	return alerts
+}
+
+// after an alert fires the next `cooldown` days can't raise another one
+// (a suppressed alert doesn't restart the cooldown). cooldown 0 is activityNotifications
+func ActivityNotificationsCooldown(expenditure []int32, d int32, cooldown int) int32 {
+	alerts := int32(0)
+	last := -1 // day of the last raised alert
+	for _, day := range ActivityNotificationDays(expenditure, d) {
+		if last != -1 && day-last <= cooldown {
+			continue
+		}
+		alerts++
+		last = day
+	}
+	return alerts
+}
+
+// activityNotifications with the median kept as a fraction and the check done in
+// integers (exceedsMedian), so no float rounding can move a borderline day
+func ActivityNotificationsExact(expenditure []int32, d int32) int32 {
+	if d <= 0 || len(expenditure) <= int(d) {
+		return 0
+	}
+	window := NewWindowCounter()
+	for i := 0; i < int(d); i++ {
+		window.Add(expenditure[i])
+	}
+	alerts := int32(0)
+	for i := int(d); i < len(expenditure); i++ {
+		num, den := window.MedianFrac()
+		if exceedsMedian(expenditure[i], num, den) {
+			alerts++
+		}
+		window.Remove(expenditure[i-int(d)])
+		window.Add(expenditure[i])
+	}
+	return alerts
+}
+
+// alerts with a per day threshold, day i alerts when spend >= multipliers[i] * median.
+// days past the end of multipliers reuse the last one, no multipliers means the usual 2
+func ActivityNotificationsSchedule(expenditure []int32, d int32, multipliers []float64) int32 {
+	_, days := ActivityNotificationsDetailed(expenditure, d)
+	alerts := int32(0)
+	for _, day := range days {
+		mult := 2.0
+		if len(multipliers) > 0 {
+			mult = multipliers[min(day.Index, len(multipliers)-1)]
+		}
+		if float64(day.Spend) >= mult*day.Median {
+			alerts++
+		}
+	}
+	return alerts
+}
+
+// activityNotifications on one long history split into `shards` pieces run concurrently.
+// each shard owns a run of alert days and gets the d days before its first one as warmup
+// (its first day needs a full window too), so every alert day is counted by exactly one shard
+func ActivityNotificationsParallel(expenditure []int32, d int32, shards int) int32 {
+	if d <= 0 || len(expenditure) <= int(d) {
+		return 0
+	}
+	days := len(expenditure) - int(d) // alert days are d..len-1
+	shards = max(1, min(shards, days))
+
+	// shard s owns alert days [owned[s][0], owned[s][1])
+	owned := make([][2]int, shards)
+	for s := range owned {
+		owned[s] = [2]int{int(d) + days*s/shards, int(d) + days*(s+1)/shards}
+	}
+	alerts, _ := RunPool(context.Background(), owned, shards, func(_ context.Context, days [2]int) (int32, error) {
+		return activityNotifications(expenditure[days[0]-int(d):days[1]], d), nil
+	})
+
+	total := int32(0)
+	for _, a := range alerts {
+		total += a
+	}
+	return total
+}
+
+// ActivityNotificationDays packed into bits, bit i (word i/64, bit i%64) is set when
+// day i alerted. one bit a day instead of an int per alert day
+func ActivityNotificationsBitset(expenditure []int32, d int32) []uint64 {
+	bits := make([]uint64, (len(expenditure)+63)/64)
+	for _, day := range ActivityNotificationDays(expenditure, d) {
+		bits[day/64] |= 1 << (day % 64)
+	}
+	return bits
+}
+
+// whether day i is set in a bitset from ActivityNotificationsBitset, false past the end
+func AlertBit(bits []uint64, i int) bool {
+	if i < 0 || i/64 >= len(bits) {
+		return false
+	}
+	return bits[i/64]&(1<<(i%64)) != 0
+}
+
+// the day (among those with a full d day window) whose trailing median is highest,
+// the earliest one on a tie. -1, 0 when no day has a full window
+func MaxMedianDay(expenditure []int32, d int32) (dayIndex int, median float64) {
+	_, days := ActivityNotificationsDetailed(expenditure, d)
+	dayIndex = -1
+	for _, day := range days {
+		if dayIndex == -1 || day.Median > median {
+			dayIndex, median = day.Index, day.Median
+		}
+	}
+	return dayIndex, median
+}
+
+// the most each day could cost without alerting, worked out one day at a time with
+// the days before it spending their caps, so spending every cap in place of expenditure
+// raises no alert. an alert is spend >= 2*median, and 2*median is a whole number
+// (medians are whole or .5), so a cap is 2*median - 1 kept to 0..200.
+// -1 for days before the window fills, and for days whose median is 0 since even
+// spending 0 alerts there. a -1 day counts in later windows at what it really spent
+func MinSpendToAvoidAlerts(expenditure []int32, d int32) []int32 {
+	caps := make([]int32, len(expenditure))
+	for i := range caps {
+		caps[i] = -1
+	}
+	if d <= 0 || len(expenditure) <= int(d) {
+		return caps
+	}
+	spent := append([]int32{}, expenditure...) // what each day costs once capped
+	window := NewWindowCounter()
+	for i := 0; i < int(d); i++ {
+		window.Add(spent[i])
+	}
+	for i := int(d); i < len(caps); i++ {
+		if num, den := window.MedianFrac(); num > 0 {
+			caps[i] = int32(min(2*num/den-1, windowValues-1))
+			spent[i] = caps[i]
+		}
+		window.Remove(spent[i-int(d)])
+		window.Add(spent[i])
+	}
+	return caps
+}
+
+// ActivityMonitor is activityNotifications for a feed that arrives one day at a time,
+// the last d days sit in a ring buffer with their counts in a WindowCounter
+type ActivityMonitor struct {
+	d      int32
+	ring   []int32 // ring[next] is the oldest day once the window is full
+	next   int
+	window *WindowCounter
+}
+
+// values pushed have to be in 0..200 like activityNotifications
+func NewActivityMonitor(d int32) *ActivityMonitor {
+	return &ActivityMonitor{d: d, ring: make([]int32, 0, max(d, 0)), window: NewWindowCounter()}
+}
+
+// adds today's spend and says whether it alerts against the d days before it,
+// false until d days have been seen
+func (m *ActivityMonitor) Push(v int32) (alert bool) {
+	if m.d <= 0 {
+		return false
+	}
+	if len(m.ring) < int(m.d) {
+		m.ring = append(m.ring, v)
+		m.window.Add(v)
+		return false
+	}
+	num, den := m.window.MedianFrac()
+	alert = exceedsMedian(v, num, den)
+
+	m.window.Remove(m.ring[m.next])
+	m.window.Add(v)
+	m.ring[m.next] = v
+	m.next = (m.next + 1) % int(m.d)
+	return alert
+}
+
+// alerts if no day could cost more than spendCap: every day is clamped first, so the
+// medians are worked out from clamped days too. compare with activityNotifications
+// on the raw days to see how many the cap avoids
+func AlertsUnderCap(expenditure []int32, d int32, spendCap int32) int32 {
+	capped := make([]int32, len(expenditure))
+	for i, v := range expenditure {
+		capped[i] = min(v, spendCap)
+	}
+	return ActivityNotificationsAuto(capped, d)
+}
+
+// rates[i] = fraction of the last rateWindow days up to and including day i that
+// alerted, days too early for a full d day window count as quiet. the first days
+// divide by however many days there are so far. empty for rateWindow <= 0
+func AlertRate(expenditure []int32, d int32, rateWindow int) []float64 {
+	rates := []float64{}
+	if rateWindow <= 0 {
+		return rates
+	}
+	alerted := make([]bool, len(expenditure))
+	for _, day := range ActivityNotificationDays(expenditure, d) {
+		alerted[day] = true
+	}
+	inWindow := 0 // alerts among the last rateWindow days
+	for i := range expenditure {
+		if alerted[i] {
+			inWindow++
+		}
+		if i >= rateWindow && alerted[i-rateWindow] {
+			inWindow--
+		}
+		rates = append(rates, float64(inWindow)/float64(min(i+1, rateWindow)))
+	}
+	return rates
+}
+
+// counts[j] = how many of the d days in front of day d+j spent strictly more than their
+// median, one per day with a full window like ActivityNotificationsDetailed.
+// values equal to the median don't count. values have to be in 0..200
+func ActivityOverMedianCounts(expenditure []int32, d int32) []int {
+	counts := []int{}
+	if d <= 0 || len(expenditure) <= int(d) {
+		return counts
+	}
+	window := NewWindowCounter()
+	for i := 0; i < int(d); i++ {
+		window.Add(expenditure[i])
+	}
+	for i := int(d); i < len(expenditure); i++ {
+		counts = append(counts, window.CountAbove(window.MedianFrac()))
+		window.Remove(expenditure[i-int(d)])
+		window.Add(expenditure[i])
+	}
+	return counts
+}
+
+// counts[i] = activityNotifications(expenditure[:i+1], d), one pass of ActivityMonitor
+// since a prefix's alerts are just the alerts of its days
+func ActivityNotificationsPrefixCounts(expenditure []int32, d int32) []int32 {
+	counts := make([]int32, len(expenditure))
+	monitor := NewActivityMonitor(d)
+	total := int32(0)
+	for i, spend := range expenditure {
+		if monitor.Push(spend) {
+			total++
+		}
+		counts[i] = total
+	}
+	return counts
+}
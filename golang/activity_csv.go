@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// reads expenditures from CSV, either one per line or all on one comma separated row
+// (or a mix), then counts alerts. a bad number fails with its line number
+func ActivityNotificationsCSV(r io.Reader, d int32) (int32, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // rows can have any number of values
+	reader.TrimLeadingSpace = true
+
+	expenditure := []int32{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, err // csv.ParseError already has the line
+		}
+		for i, field := range record {
+			v, err := strconv.ParseInt(strings.TrimSpace(field), 10, 32)
+			if err != nil {
+				line, _ := reader.FieldPos(i)
+				return 0, fmt.Errorf("line %d: %w", line, err)
+			}
+			expenditure = append(expenditure, int32(v))
+		}
+	}
+	// Auto, since values from a file aren't guaranteed to be in 0..200
+	return ActivityNotificationsAuto(expenditure, d), nil
+}
+
+// writes DayResults (ActivityNotificationsDetailed's days) as CSV with a header row,
+// columns index,spend,median,alert. the median keeps every digit so it reads back exact
+func WriteResultsCSV(w io.Writer, results []DayResult) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"index", "spend", "median", "alert"}); err != nil {
+		return err
+	}
+	for _, day := range results {
+		record := []string{
+			strconv.Itoa(day.Index),
+			strconv.FormatInt(int64(day.Spend), 10),
+			strconv.FormatFloat(day.Median, 'f', -1, 64),
+			strconv.FormatBool(day.Alert),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
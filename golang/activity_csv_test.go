@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestActivityNotificationsCSV(t *testing.T) {
+	good := "2,3,4,2\n3\n6, 8\n4,5\n" // the sample, split across rows
+	got, err := ActivityNotificationsCSV(strings.NewReader(good), 5)
+	if err != nil || got != 2 {
+		t.Errorf("ActivityNotificationsCSV(sample) = %d, %v, want 2, nil", got, err)
+	}
+
+	bad := "2,3,4\n2,3\n6,eight\n4,5\n"
+	_, err = ActivityNotificationsCSV(strings.NewReader(bad), 5)
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("bad row: err = %v, want one naming line 3", err)
+	}
+}
+
+func TestWriteResultsCSV(t *testing.T) {
+	_, days := ActivityNotificationsDetailed([]int32{1, 4, 3, 10, 2, 9}, 2) // medians 2.5 and whole ones
+	var buf bytes.Buffer
+	if err := WriteResultsCSV(&buf, days); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"index", "spend", "median", "alert"}; !slices.Equal(records[0], want) {
+		t.Errorf("header = %v, want %v", records[0], want)
+	}
+	back := []DayResult{}
+	for _, r := range records[1:] {
+		index, err1 := strconv.Atoi(r[0])
+		spend, err2 := strconv.ParseInt(r[1], 10, 32)
+		median, err3 := strconv.ParseFloat(r[2], 64)
+		alert, err4 := strconv.ParseBool(r[3])
+		if err := errors.Join(err1, err2, err3, err4); err != nil {
+			t.Fatalf("row %v: %v", r, err)
+		}
+		back = append(back, DayResult{Index: index, Spend: int32(spend), Median: median, Alert: alert})
+	}
+	if !slices.Equal(back, days) {
+		t.Errorf("read back %v, wrote %v", back, days)
+	}
+}
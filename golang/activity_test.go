@@ -0,0 +1,585 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// seeded random spend in 0..200, what activityNotifications accepts
+func randomExpenditure(rng *rand.Rand, n int) []int32 {
+	e := make([]int32, n)
+	for i := range e {
+		e[i] = int32(rng.Intn(windowValues))
+	}
+	return e
+}
+
+func TestActivityNotifications2NearMaxInt32(t *testing.T) {
+	const big = math.MaxInt32
+	cases := []struct {
+		exp  []int32
+		d    int32
+		want int32
+	}{
+		{[]int32{1 << 30, 1 << 30, big}, 2, 0},       // 2 * median is 2^31, one past MaxInt32
+		{[]int32{1 << 29, 1 << 29, 1 << 30}, 2, 1},   // exactly 2 * median
+		{[]int32{1, 1, big}, 2, 1},                   // way over
+		{[]int32{big, big - 1, big}, 2, 0},           // median near max, spend can't double it
+		{[]int32{big, 1, big - 2, big - 1}, 1, 1},    // a window of 1, only 1 -> big-2 alerts
+		{[]int32{big / 2, big / 2, big - 1}, 2, 1},   // 2 * (big/2) is big-1
+		{[]int32{big/2 + 1, big / 2, big - 1}, 2, 0}, // odd sum, median isn't a whole number
+	}
+	for _, c := range cases {
+		in := slices.Clone(c.exp)
+		if got := activityNotifications2(in, c.d); got != c.want {
+			t.Errorf("activityNotifications2(%v, %d) = %d, want %d", c.exp, c.d, got, c.want)
+		}
+		if !slices.Equal(in, c.exp) {
+			t.Errorf("activityNotifications2 reordered its input: %v, was %v", in, c.exp)
+		}
+	}
+}
+
+func TestActivityNotificationsAutoBranchesAgree(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		e := randomExpenditure(rng, rng.Intn(120))
+		d := int32(1 + rng.Intn(60))
+		counting, sorting := activityNotifications(e, d), activityNotifications2(e, d)
+		if counting != sorting {
+			t.Fatalf("d=%d %v: counting sort says %d, sort based %d", d, e, counting, sorting)
+		}
+		if got := ActivityNotificationsAuto(e, d); got != counting {
+			t.Fatalf("ActivityNotificationsAuto(%v, %d) = %d, want %d", e, d, got, counting)
+		}
+	}
+
+	small := []int32{2, 3, 4, 2, 3, 6, 8, 4, 5}
+	cases := []struct {
+		exp  []int32
+		d    int32
+		want bool
+	}{
+		{small, 5, false},
+		{small, 38, false}, // 38*log2(38) is just under 201
+		{small, 39, true},
+		{[]int32{1, 500, 2}, 100, false}, // 500 doesn't fit the counts array
+		{[]int32{-1, 2}, 100, false},
+	}
+	for _, c := range cases {
+		if got := useCountingSort(c.exp, c.d); got != c.want {
+			t.Errorf("useCountingSort(%v, %d) = %v, want %v", c.exp, c.d, got, c.want)
+		}
+	}
+}
+
+// the crossover ActivityNotificationsAuto assumes: sort wins at small d, counting at big d
+func BenchmarkActivityNotificationsBranches(b *testing.B) {
+	e := randomExpenditure(rand.New(rand.NewSource(1)), 5000)
+	for _, d := range []int32{8, 39, 500} {
+		b.Run(fmt.Sprintf("counting/d=%d", d), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				activityNotifications(e, d)
+			}
+		})
+		b.Run(fmt.Sprintf("sort/d=%d", d), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				activityNotifications2(e, d)
+			}
+		})
+		b.Run(fmt.Sprintf("auto/d=%d", d), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ActivityNotificationsAuto(e, d)
+			}
+		})
+	}
+}
+
+// median of w by sorting a copy, the reference for the counting versions
+func sortedMedian(w []int32) float64 {
+	s := slices.Clone(w)
+	slices.Sort(s)
+	if len(s)%2 == 1 {
+		return float64(s[len(s)/2])
+	}
+	return (float64(s[len(s)/2-1]) + float64(s[len(s)/2])) / 2
+}
+
+// alerts[i] for day i, from activityNotifications on the prefixes ending at i
+func alertDays(e []int32, d int32) []bool {
+	alerts := make([]bool, len(e))
+	for i := range e {
+		alerts[i] = activityNotifications(e[:i+1], d) > activityNotifications(e[:i], d)
+	}
+	return alerts
+}
+
+func TestActivityWindowStats(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		e := randomExpenditure(rng, rng.Intn(40))
+		d := int32(1 + rng.Intn(8))
+		stats := ActivityWindowStats(e, d)
+		if want := max(0, len(e)-int(d)); len(stats) != want {
+			t.Fatalf("%d stats for %d days and d=%d, want %d", len(stats), len(e), d, want)
+		}
+		alerts := alertDays(e, d)
+		for k, st := range stats {
+			i := k + int(d)
+			sum := int64(0)
+			for _, v := range e[i-int(d) : i] {
+				sum += int64(v)
+			}
+			if st.Sum != sum || st.Median != sortedMedian(e[i-int(d):i]) || st.Alert != alerts[i] {
+				t.Fatalf("day %d of %v, d=%d: got %+v, want sum %d median %v alert %v",
+					i, e, d, st, sum, sortedMedian(e[i-int(d):i]), alerts[i])
+			}
+		}
+	}
+}
+
+func TestActivityNotificationsBatch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	histories := make([][]int32, 50)
+	for i := range histories {
+		histories[i] = randomExpenditure(rng, rng.Intn(200))
+	}
+	for _, concurrency := range []int{1, 4, 100} {
+		got := ActivityNotificationsBatch(histories, 5, concurrency)
+		if len(got) != len(histories) {
+			t.Fatalf("concurrency %d: %d results for %d histories", concurrency, len(got), len(histories))
+		}
+		for i, h := range histories {
+			if want := activityNotifications(h, 5); got[i] != want {
+				t.Errorf("concurrency %d: history %d got %d alerts, want %d", concurrency, i, got[i], want)
+			}
+		}
+	}
+}
+
+// concurrency 1 is the sequential baseline, the others should speed up with the cores available
+func BenchmarkActivityNotificationsBatch(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	histories := make([][]int32, 64)
+	for i := range histories {
+		histories[i] = randomExpenditure(rng, 5000)
+	}
+	for _, concurrency := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ActivityNotificationsBatch(histories, 30, concurrency)
+			}
+		})
+	}
+}
+
+func TestActivityNotificationsDetailed(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for trial := 0; trial < 100; trial++ {
+		e := randomExpenditure(rng, rng.Intn(40))
+		d := int32(1 + rng.Intn(8))
+		total, days := ActivityNotificationsDetailed(e, d)
+		alerts := int32(0)
+		for _, day := range days {
+			if day.Alert {
+				alerts++
+			}
+			if day.Spend != e[day.Index] || day.Median != sortedMedian(e[day.Index-int(d):day.Index]) {
+				t.Fatalf("%v d=%d: bad day %+v", e, d, day)
+			}
+		}
+		if total != alerts || total != activityNotifications(e, d) {
+			t.Fatalf("%v d=%d: total %d, %d days flagged, activityNotifications says %d",
+				e, d, total, alerts, activityNotifications(e, d))
+		}
+	}
+}
+
+func TestWindowCounter(t *testing.T) {
+	w := NewWindowCounter()
+	if w.Median() != 0 || w.Len() != 0 {
+		t.Fatalf("empty window: median %v len %d", w.Median(), w.Len())
+	}
+	for _, v := range []int32{2, 3, 4, 2, 3} {
+		w.Add(v)
+	}
+	if got := w.Median(); got != 3 {
+		t.Errorf("Median of 2 3 4 2 3 = %v, want 3", got)
+	}
+	// slide: 2 leaves, 6 comes in -> 3 4 2 3 6
+	w.Remove(2)
+	w.Add(6)
+	if got := w.Median(); got != 3 || w.Len() != 5 {
+		t.Errorf("after sliding: median %v len %d, want 3 and 5", got, w.Len())
+	}
+	w.Remove(3) // 4 2 3 6, even count
+	if num, den := w.MedianFrac(); num != 7 || den != 2 || w.Median() != 3.5 {
+		t.Errorf("MedianFrac of 4 2 3 6 = %d/%d, want 7/2", num, den)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	e := randomExpenditure(rng, 300)
+	const d = 7
+	w = NewWindowCounter()
+	for i, v := range e {
+		w.Add(v)
+		if i >= d {
+			w.Remove(e[i-d])
+		}
+		lo := max(0, i-d+1)
+		if got, want := w.Median(), sortedMedian(e[lo:i+1]); got != want {
+			t.Fatalf("window %v: Median %v, want %v", e[lo:i+1], got, want)
+		}
+	}
+}
+
+func TestSlidingMedianHeap(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		values := make([]int32, rng.Intn(60))
+		for i := range values {
+			values[i] = rng.Int31() - math.MaxInt32/2 // any int32, negatives too
+			if rng.Intn(4) == 0 && i > 0 {
+				values[i] = values[rng.Intn(i)] // repeats, so lazy deletion sees duplicates
+			}
+		}
+		window := 1 + rng.Intn(10)
+		got := SlidingMedianHeap(values, window)
+		if want := max(0, len(values)-window+1); len(got) != want {
+			t.Fatalf("%d medians for %d values and window %d, want %d", len(got), len(values), window, want)
+		}
+		for i, m := range got {
+			if want := sortedMedian(values[i : i+window]); m != want {
+				t.Fatalf("window %v: median %v, want %v", values[i:i+window], m, want)
+			}
+		}
+	}
+}
+
+func TestActivityNotificationsCooldown(t *testing.T) {
+	e := []int32{1, 1, 10, 1, 10, 1, 10, 10} // d=1 alerts on days 2, 4 and 6
+	for cooldown, want := range map[int]int32{0: 3, 1: 3, 2: 2, 3: 2, 4: 1} {
+		if got := ActivityNotificationsCooldown(e, 1, cooldown); got != want {
+			t.Errorf("cooldown %d: %d alerts, want %d", cooldown, got, want)
+		}
+	}
+	sample := []int32{2, 3, 4, 2, 3, 6, 8, 4, 5}
+	if got := ActivityNotificationsCooldown(sample, 5, 0); got != activityNotifications(sample, 5) {
+		t.Errorf("cooldown 0 = %d, want activityNotifications' %d", got, activityNotifications(sample, 5))
+	}
+}
+
+func TestActivityNotificationsExact(t *testing.T) {
+	// medians 2.5 and 99.5, spend exactly twice the median alerts, one less doesn't
+	cases := []struct {
+		exp  []int32
+		d    int32
+		want int32
+	}{
+		{[]int32{2, 3, 5}, 2, 1},
+		{[]int32{2, 3, 4}, 2, 0},
+		{[]int32{99, 100, 199}, 2, 1},
+		{[]int32{99, 100, 198}, 2, 0},
+		{[]int32{1, 2, 3, 4, 5}, 4, 1}, // median of 1 2 3 4 is 2.5
+	}
+	for _, c := range cases {
+		if got := ActivityNotificationsExact(c.exp, c.d); got != c.want {
+			t.Errorf("ActivityNotificationsExact(%v, %d) = %d, want %d", c.exp, c.d, got, c.want)
+		}
+	}
+
+	// against the integer form of spend >= 2 * median: spend >= the two middles added
+	// (even window) or twice the middle (odd)
+	rng := rand.New(rand.NewSource(4))
+	for trial := 0; trial < 200; trial++ {
+		e := randomExpenditure(rng, rng.Intn(40))
+		d := int32(1 + rng.Intn(8))
+		want := int32(0)
+		for i := int(d); i < len(e); i++ {
+			w := slices.Clone(e[i-int(d) : i])
+			slices.Sort(w)
+			twice := 2 * w[len(w)/2]
+			if len(w)%2 == 0 {
+				twice = w[len(w)/2-1] + w[len(w)/2]
+			}
+			if e[i] >= twice {
+				want++
+			}
+		}
+		if got := ActivityNotificationsExact(e, d); got != want {
+			t.Fatalf("ActivityNotificationsExact(%v, %d) = %d, want %d", e, d, got, want)
+		}
+	}
+}
+
+func TestActivityNotificationsSchedule(t *testing.T) {
+	// d=1, days 1 and 3 spend 2.5x the day before, day 2 doesn't
+	e := []int32{10, 25, 10, 25}
+	cases := []struct {
+		multipliers []float64
+		want        int32
+	}{
+		{nil, 2},               // the usual 2
+		{[]float64{3, 2}, 2},   // day 0 has no window, 2 from day 1 on
+		{[]float64{2, 3}, 0},   // 3 from day 1 on, 2.5x isn't enough
+		{[]float64{2, 2.5}, 2}, // exactly 2.5x still alerts
+	}
+	for _, c := range cases {
+		if got := ActivityNotificationsSchedule(e, 1, c.multipliers); got != c.want {
+			t.Errorf("ActivityNotificationsSchedule(%v, 1, %v) = %d, want %d", e, c.multipliers, got, c.want)
+		}
+	}
+
+	// day 4 is the switch: before it 3x is needed, from it 2x
+	e = []int32{10, 25, 10, 25, 10, 25}
+	sched := []float64{3, 3, 3, 3, 2}
+	if got := ActivityNotificationsSchedule(e, 1, sched); got != 1 {
+		t.Errorf("alerts with the switch on day 4 = %d, want 1 (day 5 only)", got)
+	}
+	sched = []float64{3, 3, 3, 2}
+	if got := ActivityNotificationsSchedule(e, 1, sched); got != 2 {
+		t.Errorf("alerts with the switch on day 3 = %d, want 2 (days 3 and 5)", got)
+	}
+}
+
+func TestActivityNotificationsParallel(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	for trial := 0; trial < 50; trial++ {
+		e := randomExpenditure(rng, rng.Intn(300))
+		d := int32(1 + rng.Intn(20))
+		want := activityNotifications(e, d)
+		for _, shards := range []int{1, 2, 3, 7, 64, 1000} {
+			if got := ActivityNotificationsParallel(e, d, shards); got != want {
+				t.Fatalf("%d days, d=%d, %d shards: %d alerts, want %d", len(e), d, shards, got, want)
+			}
+		}
+	}
+}
+
+func TestActivityNotificationsBitset(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for _, n := range []int{0, 1, 63, 64, 65, 200} {
+		e := randomExpenditure(rng, n)
+		d := int32(1 + rng.Intn(5))
+		bits := ActivityNotificationsBitset(e, d)
+		if want := (n + 63) / 64; len(bits) != want {
+			t.Fatalf("%d days: %d words, want %d", n, len(bits), want)
+		}
+		alerts := ActivityNotificationDays(e, d)
+		for i := -1; i <= n+64; i++ {
+			if got, want := AlertBit(bits, i), slices.Contains(alerts, i); got != want {
+				t.Errorf("%d days, d=%d: AlertBit(%d) = %v, want %v", n, d, i, got, want)
+			}
+		}
+	}
+}
+
+func TestMaxMedianDay(t *testing.T) {
+	cases := []struct {
+		e      []int32
+		d      int32
+		day    int
+		median float64
+	}{
+		{[]int32{2, 3, 4, 2, 3, 6, 8, 4, 5}, 5, 7, 4}, // days 7 and 8 both have 4, 7 is earlier
+		{[]int32{1, 4, 0, 0}, 2, 2, 2.5},
+		{[]int32{5, 5}, 2, -1, 0}, // no day has a full window
+		{nil, 1, -1, 0},
+	}
+	for _, c := range cases {
+		day, median := MaxMedianDay(c.e, c.d)
+		if day != c.day || median != c.median {
+			t.Errorf("MaxMedianDay(%v, %d) = %d, %v, want %d, %v", c.e, c.d, day, median, c.day, c.median)
+		}
+	}
+}
+
+func TestMinSpendToAvoidAlerts(t *testing.T) {
+	// d=1 with big swings: twice a spend runs well past 200, so the caps get clamped
+	e := []int32{90, 81, 188, 67, 87, 150, 57, 115, 89, 145, 40}
+	caps := MinSpendToAvoidAlerts(e, 1)
+	if caps[0] != -1 {
+		t.Errorf("day 0 has no window, cap %d, want -1", caps[0])
+	}
+	for i, c := range caps[1:] {
+		if c < 0 || c >= windowValues {
+			t.Fatalf("MinSpendToAvoidAlerts(%v, 1)[%d] = %d, outside 0..200", e, i+1, c)
+		}
+	}
+
+	cases := []struct {
+		e    []int32
+		d    int32
+		want []int32
+	}{
+		{[]int32{0, 0, 5}, 2, []int32{-1, -1, -1}},       // median 0, even 0 alerts
+		{[]int32{0, 4, 0, 9}, 2, []int32{-1, -1, 3, 6}},  // day 2 counts as its cap 3, so day 3 has median 3.5
+		{[]int32{0, 0, 7, 3}, 2, []int32{-1, -1, -1, 6}}, // day 2 stays at 7 in day 3's window
+		{[]int32{5}, 0, []int32{-1}},
+	}
+	for _, c := range cases {
+		if got := MinSpendToAvoidAlerts(c.e, c.d); !slices.Equal(got, c.want) {
+			t.Errorf("MinSpendToAvoidAlerts(%v, %d) = %v, want %v", c.e, c.d, got, c.want)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(8))
+	for trial := 0; trial < 600; trial++ {
+		e := randomExpenditure(rng, rng.Intn(60))
+		if trial%2 == 0 { // plenty of zeros, so some windows have median 0
+			for i := range e {
+				e[i] %= 3
+			}
+		}
+		d := int32(1 + rng.Intn(8))
+		caps := MinSpendToAvoidAlerts(e, d)
+		spent := slices.Clone(caps) // the caps, with the -1 days back at what they spent
+		unavoidable := []int{}
+		for i, c := range caps {
+			if c == -1 {
+				spent[i] = e[i]
+				if i >= int(d) {
+					unavoidable = append(unavoidable, i)
+				}
+			}
+		}
+		if got := ActivityNotificationDays(spent, d); !slices.Equal(got, unavoidable) {
+			t.Fatalf("%v, d=%d: spending %v alerts on %v, want only the -1 days %v", e, d, spent, got, unavoidable)
+		}
+		if got := ActivityNotificationsAuto(spent, d); got != int32(len(unavoidable)) {
+			t.Fatalf("%v, d=%d: Auto gives %d alerts spending %v, want %d", e, d, got, spent, len(unavoidable))
+		}
+		// and each cap is the most: one more on that day alerts it
+		for i := int(d); i < len(caps); i++ {
+			if caps[i] == -1 || caps[i] == windowValues-1 {
+				continue
+			}
+			bumped := slices.Clone(spent)
+			bumped[i]++
+			if !slices.Contains(ActivityNotificationDays(bumped, d), i) {
+				t.Fatalf("%v, d=%d: day %d spending %d over its cap doesn't alert", spent, d, i, bumped[i])
+			}
+		}
+	}
+}
+
+func TestActivityMonitor(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	for trial := 0; trial < 200; trial++ {
+		e := randomExpenditure(rng, rng.Intn(80))
+		d := int32(rng.Intn(10)) // 0 too, which never alerts
+		m := NewActivityMonitor(d)
+		got := []int{}
+		for i, v := range e {
+			if m.Push(v) {
+				got = append(got, i)
+			}
+		}
+		if want := ActivityNotificationDays(e, d); !slices.Equal(got, want) {
+			t.Fatalf("pushing %v, d=%d: alerts on %v, want %v", e, d, got, want)
+		}
+	}
+}
+
+func TestAlertsUnderCap(t *testing.T) {
+	e := []int32{2, 3, 4, 2, 3, 6, 8, 4, 5}
+	cases := []struct {
+		spendCap int32
+		want     int32
+	}{
+		{200, 2}, // nothing clamped, the sample's 2
+		{8, 2},
+		{6, 2}, // the 8 becomes 6, still twice the median of 3
+		{5, 0},
+		{0, 4}, // every day 0, and 0 >= 2*0 alerts all four
+	}
+	if got := activityNotifications(e, 5); got != 2 {
+		t.Fatalf("uncapped sample = %d alerts, want 2", got)
+	}
+	for _, c := range cases {
+		if got := AlertsUnderCap(e, 5, c.spendCap); got != c.want {
+			t.Errorf("AlertsUnderCap(sample, 5, %d) = %d, want %d", c.spendCap, got, c.want)
+		}
+	}
+}
+
+func TestAlertRate(t *testing.T) {
+	e := []int32{2, 3, 4, 2, 3, 6, 8, 4, 5} // alerts on days 5 and 6
+	cases := []struct {
+		rateWindow int
+		want       []float64
+	}{
+		{2, []float64{0, 0, 0, 0, 0, 0.5, 1, 0.5, 0}},
+		{1, []float64{0, 0, 0, 0, 0, 1, 1, 0, 0}},
+		{9, []float64{0, 0, 0, 0, 0, 1.0 / 6, 2.0 / 7, 2.0 / 8, 2.0 / 9}}, // short of a full window, divide by the days so far
+		{0, []float64{}},
+	}
+	for _, c := range cases {
+		if got := AlertRate(e, 5, c.rateWindow); !slices.Equal(got, c.want) {
+			t.Errorf("AlertRate(sample, 5, %d) = %v, want %v", c.rateWindow, got, c.want)
+		}
+	}
+}
+
+func TestActivityOverMedianCounts(t *testing.T) {
+	cases := []struct {
+		e    []int32
+		d    int32
+		want []int
+	}{
+		{[]int32{1, 2, 2, 2, 5, 0}, 4, []int{0, 1}}, // the 2s tie with the median and don't count
+		{[]int32{1, 3, 3, 3}, 2, []int{1, 0}},       // median 2 of [1 3], then all 3s
+		{[]int32{4, 4}, 2, []int{}},
+	}
+	for _, c := range cases {
+		if got := ActivityOverMedianCounts(c.e, c.d); !slices.Equal(got, c.want) {
+			t.Errorf("ActivityOverMedianCounts(%v, %d) = %v, want %v", c.e, c.d, got, c.want)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(10))
+	for trial := 0; trial < 200; trial++ {
+		e := randomExpenditure(rng, rng.Intn(40))
+		d := int32(1 + rng.Intn(8))
+		want := []int{}
+		for i := int(d); i < len(e); i++ {
+			w := e[i-int(d) : i]
+			m := sortedMedian(w)
+			want = append(want, len(slices.DeleteFunc(slices.Clone(w), func(v int32) bool { return float64(v) <= m })))
+		}
+		if got := ActivityOverMedianCounts(e, d); !slices.Equal(got, want) {
+			t.Fatalf("ActivityOverMedianCounts(%v, %d) = %v, want %v", e, d, got, want)
+		}
+	}
+}
+
+func TestActivityNotificationsPrefixCounts(t *testing.T) {
+	got := ActivityNotificationsPrefixCounts([]int32{2, 3, 4, 2, 3, 6, 8, 4, 5}, 5)
+	if want := []int32{0, 0, 0, 0, 0, 1, 2, 2, 2}; !slices.Equal(got, want) {
+		t.Errorf("prefix counts of the sample = %v, want %v", got, want)
+	}
+
+	rng := rand.New(rand.NewSource(11))
+	for trial := 0; trial < 100; trial++ {
+		e := randomExpenditure(rng, rng.Intn(60))
+		d := int32(1 + rng.Intn(8))
+		counts := ActivityNotificationsPrefixCounts(e, d)
+		if len(counts) != len(e) {
+			t.Fatalf("%d counts for %d days", len(counts), len(e))
+		}
+		if !slices.IsSorted(counts) {
+			t.Fatalf("prefix counts %v of %v go down", counts, e)
+		}
+		for i := range counts {
+			if want := activityNotifications(e[:i+1], d); counts[i] != want {
+				t.Fatalf("%v, d=%d: counts[%d] = %d, activityNotifications on the prefix says %d", e, d, i, counts[i], want)
+			}
+		}
+		if len(e) > 0 && counts[len(e)-1] != activityNotifications(e, d) {
+			t.Fatalf("%v, d=%d: last count %d isn't the full count", e, d, counts[len(e)-1])
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+// the same search as matcher.match, with the recursion turned into a stack of
+// choice points. each frame is a token that took n units starting at i, backtracking
+// pops to the newest frame that can still give one back
+type choice struct {
+	ti     int // token index
+	i      int // where the token started
+	n      int // how many it took this time
+	fewest int // the least it may take, n can go down to this
+}
+
+func (m *matcher) matchIter(tokens []token) bool {
+	stack := []choice{}
+	ti, i := 0, 0
+	for {
+		if !m.step() {
+			return false
+		}
+		ok := false
+		if ti == len(tokens) {
+			if m.prefix || i == len(m.in) {
+				m.end = i
+				if m.took != nil {
+					for _, c := range stack {
+						m.took[c.ti] = c.n
+					}
+				}
+				return true
+			}
+			if m.onFail != nil {
+				m.onFail(i, nil)
+			}
+		} else {
+			t := &tokens[ti]
+			n := 0
+			for i+n < len(m.in) && (t.max == -1 || n < t.max) && t.matches(m.in[i+n]) {
+				n++
+			}
+			if n < t.min && m.onFail != nil {
+				m.onFail(i+n, t)
+			}
+			fewest := t.min
+			if t.possessive {
+				fewest = n
+			}
+			if n >= t.min {
+				stack = append(stack, choice{ti: ti, i: i, n: n, fewest: fewest})
+				ti, i = ti+1, i+n
+				ok = true
+			}
+		}
+		if ok {
+			continue
+		}
+
+		// backtrack: newest token that can give one back does, everything after it is redone
+		for {
+			if len(stack) == 0 {
+				return false
+			}
+			top := &stack[len(stack)-1]
+			top.n--
+			if top.n >= top.fewest {
+				ti, i = top.ti+1, top.i+top.n
+				break
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestIterativeBacktrackingAgrees(t *testing.T) {
+	for _, c := range matchCases {
+		if got := MustCompile(c.pattern, WithIterativeBacktracking()).Match(c.s); got != c.want {
+			t.Errorf("iterative %q.Match(%q) = %v, want %v", c.pattern, c.s, got, c.want)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 300; trial++ {
+		src := randomPattern(rng, true)
+		rec, iter := MustCompile(src), MustCompile(src, WithIterativeBacktracking())
+		for k := 0; k < 20; k++ {
+			s := randomInput(rng, "abc", 8)
+			if got, want := iter.Match(s), rec.Match(s); got != want {
+				t.Fatalf("iterative %q.Match(%q) = %v, recursive says %v", src, s, got, want)
+			}
+			gotN, gotOK := iter.MatchPrefix(s)
+			wantN, wantOK := rec.MatchPrefix(s)
+			if gotN != wantN || gotOK != wantOK {
+				t.Fatalf("iterative %q.MatchPrefix(%q) = %d, %v, recursive says %d, %v", src, s, gotN, gotOK, wantN, wantOK)
+			}
+		}
+	}
+
+	// no call depth to run out of on a long input
+	long := strings.Repeat("ab", 100_000)
+	if !MustCompile("*.b", WithIterativeBacktracking()).Match(long) {
+		t.Error("iterative *.b doesn't match a long ab run")
+	}
+}
+
+func BenchmarkBacktracking(b *testing.B) {
+	patterns := []struct {
+		name, src, s string
+	}{
+		{"stars", "*a*a*ab", strings.Repeat("a", 40)},
+		{"dots", "*.*.*.x", strings.Repeat("ab", 20)},
+		{"alternatives", "*ab|*a*b|*.c", strings.Repeat("a", 200) + "b"},
+	}
+	for _, pat := range patterns {
+		for _, mode := range []struct {
+			name string
+			opts []Option
+		}{{"recursive", nil}, {"iterative", []Option{WithIterativeBacktracking()}}} {
+			p := MustCompile(pat.src, mode.opts...)
+			b.Run(pat.name+"/"+mode.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					p.Match(pat.s)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+// MaxBacktrackFactor is a rough static warning for patterns that can backtrack badly:
+// the longest run of neighbouring variable length repeats (stars, `{m,n}`, `?`) that
+// can trade chars with each other, because each one could match what its neighbour
+// does. `*a*a*a` is 3, on a failing input every split of the a's between them gets
+// tried, `*a*b*c` is 1 since no a can go anywhere but the first star.
+// possessive repeats never give back so they don't count, a pattern with no variable
+// repeats is 0. with alternatives the worst one counts
+func (p *Pattern) MaxBacktrackFactor() int {
+	worst := 0
+	for _, tokens := range p.alts {
+		run := 0
+		for i := range tokens {
+			t := &tokens[i]
+			switch {
+			case t.min == t.max || t.possessive:
+				run = 0
+			case run > 0 && tokensOverlap(&tokens[i-1], t):
+				run++
+			default:
+				run = 1
+			}
+			worst = max(worst, run)
+		}
+	}
+	return worst
+}
+
+// is there a char both tokens match. a predicate can't be looked into so it's
+// assumed to overlap with anything
+func tokensOverlap(a, b *token) bool {
+	if a.kind == tokPredicate || b.kind == tokPredicate {
+		return true
+	}
+	// only the edges of literals and ranges change the answer, so trying one rune
+	// per stretch between them is enough (as in distinguishingRunes)
+	points := []rune{0}
+	for _, t := range []*token{a, b} {
+		switch t.kind {
+		case tokLiteral:
+			points = append(points, t.r, t.r+1)
+		case tokClass:
+			for _, rg := range t.class.ranges {
+				points = append(points, rg[0], rg[1]+1)
+			}
+		}
+	}
+	for _, r := range points {
+		if a.matches(r) && b.matches(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestMaxBacktrackFactor(t *testing.T) {
+	cases := []struct {
+		pattern string
+		want    int
+	}{
+		{"*a*a*a", 3},
+		{"*a*b*c", 1},
+		{"abc", 0},
+		{"a?a?a?", 3},
+		{"*[ab]*b", 2},
+		{"*a*.", 2},
+		{"*ax*a", 1}, // the fixed x splits the run
+		{"*+a*a", 1}, // possessive gives nothing back
+		{"*a*b|*a*a", 2},
+	}
+	for _, c := range cases {
+		if got := MustCompile(c.pattern).MaxBacktrackFactor(); got != c.want {
+			t.Errorf("%q.MaxBacktrackFactor() = %d, want %d", c.pattern, got, c.want)
+		}
+	}
+	if a, b := MustCompile("*a*a*a").MaxBacktrackFactor(), MustCompile("*a*b*c").MaxBacktrackFactor(); a <= b {
+		t.Errorf("*a*a*a (%d) should score above *a*b*c (%d)", a, b)
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// the binary form is the token stream as it is after Compile (and Optimize if it ran),
+// so loading it skips the tokenizer:
+//
+//	"PAT" version flags src alts...
+//	alt   = count token...
+//	token = kind possessive r min max pos group [class | predicate name]
+//
+// numbers are varints, strings a length then the bytes
+const (
+	binaryMagic   = "PAT"
+	binaryVersion = 2 // 2 added token groups
+
+	binaryByteMode  = 1 << 0
+	binaryIterative = 1 << 1
+)
+
+// ErrCorruptPattern is what UnmarshalPattern wraps for data MarshalBinary couldn't have written
+var ErrCorruptPattern = errors.New("pattern: corrupt binary encoding")
+
+// MarshalBinary implements encoding.BinaryMarshaler. predicates are funcs so only
+// their names are stored, loading needs the same WithPredicates again
+func (p *Pattern) MarshalBinary() ([]byte, error) {
+	b := append([]byte(binaryMagic), binaryVersion)
+	flags := byte(0)
+	if p.byteMode {
+		flags |= binaryByteMode
+	}
+	if p.iterative {
+		flags |= binaryIterative
+	}
+	b = append(b, flags)
+	b = appendString(b, p.src)
+	b = binary.AppendUvarint(b, uint64(len(p.alts)))
+	for _, tokens := range p.alts {
+		b = binary.AppendUvarint(b, uint64(len(tokens)))
+		for _, t := range tokens {
+			b = append(b, byte(t.kind))
+			if t.possessive {
+				b = append(b, 1)
+			} else {
+				b = append(b, 0)
+			}
+			b = binary.AppendVarint(b, int64(t.r))
+			b = binary.AppendVarint(b, int64(t.min))
+			b = binary.AppendVarint(b, int64(t.max))
+			b = binary.AppendVarint(b, int64(t.pos))
+			b = appendString(b, t.group)
+			switch t.kind {
+			case tokClass:
+				if t.class.negate {
+					b = append(b, 1)
+				} else {
+					b = append(b, 0)
+				}
+				b = binary.AppendUvarint(b, uint64(len(t.class.ranges)))
+				for _, rg := range t.class.ranges {
+					b = binary.AppendVarint(b, int64(rg[0]))
+					b = binary.AppendVarint(b, int64(rg[1]))
+				}
+			case tokPredicate:
+				b = appendString(b, t.name)
+			}
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalPattern loads what MarshalBinary wrote. opts are applied first, so
+// WithPredicates here is what `\p{name}` tokens get resolved against
+func UnmarshalPattern(data []byte, opts ...Option) (*Pattern, error) {
+	p := &Pattern{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if err := p.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, replacing p's pattern
+// but keeping the predicates it already has for `\p{name}`
+func (p *Pattern) UnmarshalBinary(data []byte) error {
+	d := &decoder{data: data}
+	if string(d.bytes(len(binaryMagic))) != binaryMagic || d.byte() != binaryVersion {
+		return fmt.Errorf("%w: not a pattern, or another version", ErrCorruptPattern)
+	}
+	flags := d.byte()
+	src := d.string()
+	alts := make([][]token, d.count())
+	for a := range alts {
+		tokens := make([]token, d.count())
+		for i := range tokens {
+			t := &tokens[i]
+			t.kind = tokenKind(d.byte())
+			t.possessive = d.byte() == 1
+			t.r = rune(d.int())
+			t.min, t.max, t.pos = d.int(), d.int(), d.int()
+			t.group = d.string()
+			switch t.kind {
+			case tokLiteral, tokAny:
+			case tokClass:
+				t.class = &charClass{negate: d.byte() == 1, ranges: make([][2]rune, d.count())}
+				if len(t.class.ranges) == 0 {
+					d.fail("empty class")
+				}
+				for j := range t.class.ranges {
+					t.class.ranges[j] = [2]rune{rune(d.int()), rune(d.int())}
+					if t.class.ranges[j][0] > t.class.ranges[j][1] {
+						d.fail("class range out of order")
+					}
+				}
+			case tokPredicate:
+				t.name = d.string()
+				t.pred = p.predicates[t.name]
+				if d.err == nil && t.pred == nil {
+					return fmt.Errorf("pattern: unknown predicate %q", t.name)
+				}
+			default:
+				d.fail("unknown token kind")
+			}
+			if t.min < 0 || t.max == 0 || t.max != -1 && t.max < t.min {
+				d.fail("bad repeat")
+			}
+			if d.err != nil {
+				return d.err
+			}
+		}
+		alts[a] = tokens
+	}
+	if d.err == nil && len(d.data) > 0 {
+		d.fail("trailing bytes")
+	}
+	if d.err != nil {
+		return d.err
+	}
+	p.src, p.alts = src, alts
+	p.byteMode = flags&binaryByteMode != 0
+	p.iterative = flags&binaryIterative != 0
+	p.nfaOnce, p.nfa = sync.Once{}, nil
+	return nil
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// decoder reads what MarshalBinary appended. the first problem sticks in err and
+// everything after it reads as zero, so callers only check err now and then
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) fail(msg string) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: %s", ErrCorruptPattern, msg)
+	}
+	d.data = nil
+}
+
+func (d *decoder) bytes(n int) []byte {
+	if n < 0 || n > len(d.data) {
+		d.fail("unexpected end")
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *decoder) byte() byte {
+	if b := d.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (d *decoder) int() int {
+	v, n := binary.Varint(d.data)
+	if n <= 0 || int64(int(v)) != v {
+		d.fail("bad number")
+		return 0
+	}
+	d.data = d.data[n:]
+	return int(v)
+}
+
+// a length, capped by what's left so a corrupt one can't make a huge allocation
+func (d *decoder) count() int {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 || v > uint64(len(d.data)) {
+		d.fail("bad length")
+		return 0
+	}
+	d.data = d.data[n:]
+	return int(v)
+}
+
+func (d *decoder) string() string {
+	return string(d.bytes(d.count()))
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"unicode"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	preds := WithPredicates(map[string]func(rune) bool{"digit": unicode.IsDigit})
+	pats := []*Pattern{
+		MustCompile("a*bc.z"),
+		MustCompile("{2,3}[^a-c]x?|*+[a-z]"),
+		MustCompile(`*\p{digit}a`, preds),
+		MustCompile("é.", WithByteMode()),
+		MustCompile("(?<w>*a)b", WithIterativeBacktracking()),
+		MustCompile("*a*a").Optimize(),
+	}
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		pats = append(pats, MustCompile(randomPattern(rng, true)))
+	}
+	for _, p := range pats {
+		data, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%q: MarshalBinary: %v", p, err)
+		}
+		q, err := UnmarshalPattern(data, preds)
+		if err != nil {
+			t.Fatalf("%q: UnmarshalPattern: %v", p, err)
+		}
+		if q.String() != p.String() || q.byteMode != p.byteMode || q.iterative != p.iterative || !q.Equal(p) {
+			t.Fatalf("%q came back as %q\n%s", p, q, q.Debug())
+		}
+		for k := 0; k < 30; k++ {
+			s := randomInput(rng, "abcxz1é", 6)
+			if got, want := q.Match(s), p.Match(s); got != want {
+				t.Fatalf("%q after a round trip: Match(%q) = %v, want %v", p, s, got, want)
+			}
+		}
+	}
+}
+
+func TestUnmarshalCorrupt(t *testing.T) {
+	data, err := MustCompile("a*[b-d]|x?y").MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := 0; n < len(data); n++ { // every truncation
+		if _, err := UnmarshalPattern(data[:n]); !errors.Is(err, ErrCorruptPattern) {
+			t.Fatalf("truncated to %d bytes: err = %v, want ErrCorruptPattern", n, err)
+		}
+	}
+	if _, err := UnmarshalPattern(append(data, 0)); !errors.Is(err, ErrCorruptPattern) {
+		t.Errorf("trailing byte: err = %v, want ErrCorruptPattern", err)
+	}
+
+	withPred, _ := MustCompile(`\p{digit}`, WithPredicates(map[string]func(rune) bool{"digit": unicode.IsDigit})).MarshalBinary()
+	if _, err := UnmarshalPattern(withPred); err == nil {
+		t.Error("loading a predicate with no WithPredicates returned no error")
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// MatchChunks is Match on the chunks joined end to end, without joining them.
+// the NFA is stepped one unit at a time, so the chunks can be cut anywhere, even in
+// the middle of a rune in rune mode: its bytes wait until the next chunk finishes it.
+// possessive patterns have no NFA and get the chunks joined for Match after all
+func (p *Pattern) MatchChunks(chunks [][]byte) bool {
+	if p.hasPossessive() {
+		return p.Match(string(bytes.Join(chunks, nil)))
+	}
+	p.nfaOnce.Do(func() { p.nfa = compileNFA(p.alts) })
+	prog := p.nfa
+
+	pcs := prog.closure([]int{prog.start})
+	var pending [utf8.UTFMax]byte // start of a rune cut off by the chunk end
+	held := 0
+	// decode what's held, all of it at the end. a bad byte is one RuneError, as in []rune(s)
+	flush := func(final bool) {
+		for held > 0 && (final || utf8.FullRune(pending[:held])) {
+			r, size := utf8.DecodeRune(pending[:held])
+			pcs = prog.step(pcs, r)
+			held = copy(pending[:], pending[size:held])
+		}
+	}
+	for _, chunk := range chunks {
+		for _, b := range chunk {
+			if len(pcs) == 0 {
+				return false
+			}
+			if p.byteMode {
+				pcs = prog.step(pcs, rune(b))
+				continue
+			}
+			pending[held] = b
+			held++
+			flush(false)
+		}
+	}
+	flush(true)
+	return prog.accepts(pcs)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// s cut at random places, empty chunks included
+func randomChunks(rng *rand.Rand, s string) [][]byte {
+	chunks := [][]byte{}
+	for len(s) > 0 {
+		n := rng.Intn(min(len(s), 3) + 1)
+		chunks = append(chunks, []byte(s[:n]))
+		s = s[n:]
+	}
+	return chunks
+}
+
+func TestMatchChunks(t *testing.T) {
+	cases := []struct {
+		pattern string
+		chunks  [][]byte
+		want    bool
+	}{
+		{"a.c", [][]byte{[]byte("a\xc3"), []byte("\xa9c")}, true}, // é cut in half
+		{"a.c", [][]byte{[]byte("a"), {0xc3}, {0xa9}, []byte("c")}, true},
+		{"..", [][]byte{[]byte("a\xe9")}, true}, // a lone lead byte at the end is one char
+		{"*a", [][]byte{nil, []byte("aa"), {}, []byte("a")}, true},
+		{"*a", [][]byte{[]byte("aa"), []byte("b")}, false},
+		{"", nil, true},
+		{"*+ab", [][]byte{[]byte("aa"), []byte("b")}, true}, // possessive, joined for Match
+	}
+	for _, c := range cases {
+		if got := MustCompile(c.pattern).MatchChunks(c.chunks); got != c.want {
+			t.Errorf("%q.MatchChunks(%q) = %v, want %v", c.pattern, c.chunks, got, c.want)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(14))
+	for trial := 0; trial < 500; trial++ {
+		opts := []Option{}
+		if trial%4 == 0 {
+			opts = append(opts, WithByteMode())
+		}
+		p := MustCompile(randomPattern(rng, trial%3 == 0), opts...)
+		for k := 0; k < 10; k++ {
+			s := "" // raw pieces, so bad and cut off UTF-8 turns up too
+			for n := rng.Intn(7); n > 0; n-- {
+				s += []string{"a", "b", "é", "\xff", "\xc3"}[rng.Intn(5)]
+			}
+			chunks := randomChunks(rng, s)
+			if got, want := p.MatchChunks(chunks), p.Match(s); got != want {
+				t.Fatalf("%q.MatchChunks(%q) = %v, Match(%q) = %v", p.src, chunks, got, s, want)
+			}
+		}
+	}
+}
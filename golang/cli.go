@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// command line modes, with no mode flag main just runs its regex checks
+//
+//	go run . -match -pattern='a.c' < lines.txt
+//	go run . -grep -pattern='*[a-z]' < lines.txt
+func runCLI() (handled bool) {
+	matchMode := flag.Bool("match", false, "print MATCH / NO MATCH for every stdin line against -pattern")
+	grepMode := flag.Bool("grep", false, "print stdin lines containing -pattern, matches highlighted on a terminal")
+	pattern := flag.String("pattern", "", "pattern in the regularExpression dialect")
+	flag.Parse()
+
+	var err error
+	switch {
+	case *matchMode:
+		err = runMatch(os.Stdin, os.Stdout, *pattern)
+	case *grepMode:
+		err = runGrep(os.Stdin, os.Stdout, *pattern, isTerminal(os.Stdout))
+	default:
+		return false
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return true
+}
+
+// compiles pattern first so a bad one fails before any input is read
+func runMatch(in io.Reader, out io.Writer, pattern string) error {
+	p, err := Compile(pattern)
+	if err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := scanner.Text()
+		prefix := "NO MATCH"
+		if p.Match(line) {
+			prefix = "MATCH"
+		}
+		if _, err := fmt.Fprintf(out, "%s: %s\n", prefix, line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// like grep: only lines with a match somewhere are printed, matched spans
+// colored when color is set (keeps escape codes out of pipes and files)
+func runGrep(in io.Reader, out io.Writer, pattern string, color bool) error {
+	p, err := Compile(pattern)
+	if err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := scanner.Text()
+		spans := p.FindAllIndex(line, -1)
+		if len(spans) == 0 {
+			continue
+		}
+		if color {
+			line = highlight(line, spans)
+		}
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+const (
+	colorMatch = "\x1b[1;31m" // bold red
+	colorReset = "\x1b[0m"
+)
+
+// wraps each [start, end) byte span of line in colorMatch / colorReset,
+// spans in order and not overlapping, as FindAllIndex gives them
+func highlight(line string, spans [][]int) string {
+	var b strings.Builder
+	last := 0
+	for _, sp := range spans {
+		if sp[0] == sp[1] {
+			continue // nothing to color
+		}
+		b.WriteString(line[last:sp[0]])
+		b.WriteString(colorMatch)
+		b.WriteString(line[sp[0]:sp[1]])
+		b.WriteString(colorReset)
+		last = sp[1]
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
+
+// character device = terminal, near enough without pulling in x/term
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
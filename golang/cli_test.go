@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunMatch(t *testing.T) {
+	in := bytes.NewReader([]byte("abc\nabd\naxc\n\n"))
+	var out bytes.Buffer
+	if err := runMatch(in, &out, "a.c"); err != nil {
+		t.Fatal(err)
+	}
+	want := "MATCH: abc\nNO MATCH: abd\nMATCH: axc\nNO MATCH: \n"
+	if out.String() != want {
+		t.Errorf("runMatch output:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	if err := runMatch(strings.NewReader("abc\n"), &out, "a*"); err == nil {
+		t.Error("runMatch with a bad pattern returned no error")
+	}
+	if out.Len() != 0 {
+		t.Errorf("runMatch wrote %q before rejecting the pattern", out.String())
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	line := "xabcyabz"
+	got := highlight(line, [][]int{{1, 3}, {5, 7}})
+	want := "x" + colorMatch + "ab" + colorReset + "cy" + colorMatch + "ab" + colorReset + "z"
+	if got != want {
+		t.Errorf("highlight = %q, want %q", got, want)
+	}
+	if got := highlight(line, [][]int{{2, 2}}); got != line {
+		t.Errorf("empty span changed the line: %q", got)
+	}
+	if got := highlight(line, MustCompile("ab").FindAllIndex(line, -1)); got != want {
+		t.Errorf("highlight with FindAllIndex spans = %q, want %q", got, want)
+	}
+}
+
+func TestRunGrep(t *testing.T) {
+	in := "no match\nxaby\nab ab\n"
+	var out bytes.Buffer
+	if err := runGrep(strings.NewReader(in), &out, "ab", false); err != nil {
+		t.Fatal(err)
+	}
+	if want := "xaby\nab ab\n"; out.String() != want {
+		t.Errorf("runGrep without color = %q, want %q", out.String(), want)
+	}
+	out.Reset()
+	if err := runGrep(strings.NewReader(in), &out, "ab", true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "x"+colorMatch+"ab"+colorReset+"y") {
+		t.Errorf("runGrep with color = %q, matches not wrapped", out.String())
+	}
+}
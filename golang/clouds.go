@@ -0,0 +1,179 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+)
+
+// cheapest way to the last cloud when a 1 step costs stepCost and a 2 jump costs jumpCost.
+// dp[i] = cheapest cost to stand on cloud i, -1 if the end can't be reached
+func JumpingOnCloudsScore(c []int32, stepCost, jumpCost int) int {
+	n := len(c)
+	if n == 0 {
+		return 0
+	}
+	const unreachable = -1
+	dp := make([]int, n)
+	for i := 1; i < n; i++ {
+		dp[i] = unreachable
+		if c[i] == 1 {
+			continue // thunderhead
+		}
+		if dp[i-1] != unreachable {
+			dp[i] = dp[i-1] + stepCost
+		}
+		if i >= 2 && dp[i-2] != unreachable && (dp[i] == unreachable || dp[i-2]+jumpCost < dp[i]) {
+			dp[i] = dp[i-2] + jumpCost
+		}
+	}
+	return dp[n-1]
+}
+
+// maximal runs of safe clouds as [start, end] (inclusive), a lone safe cloud is [i, i]
+func CloudSegments(c []int32) [][2]int {
+	segments := [][2]int{}
+	start := -1 // start of the run we're in, -1 = on a thunderhead
+	for i, cloud := range c {
+		switch {
+		case cloud == 0 && start == -1:
+			start = i
+		case cloud == 1 && start != -1:
+			segments = append(segments, [2]int{start, i - 1})
+			start = -1
+		}
+	}
+	if start != -1 {
+		segments = append(segments, [2]int{start, len(c) - 1})
+	}
+	return segments
+}
+
+// jumpingOnClouds over `trials` random valid layouts of length n, counts[j] = how many
+// layouts took j jumps. same seed, same layouts. every j is between (n-1)/2 rounded up
+// (nothing but 2-jumps) and n-1 (nothing but 1-steps)
+func JumpCountDistribution(n int, trials int, seed int64) map[int32]int {
+	rng := rand.New(rand.NewSource(seed))
+	counts := map[int32]int{}
+	if n < 1 {
+		return counts
+	}
+	for t := 0; t < trials; t++ {
+		counts[jumpingOnClouds(randomClouds(rng, n))]++
+	}
+	return counts
+}
+
+// random layout the problem allows: both ends safe and no two thunderheads in a row
+func randomClouds(rng *rand.Rand, n int) []int32 {
+	c := make([]int32, n)
+	for i := 1; i < n-1; i++ {
+		if c[i-1] == 0 && rng.Intn(2) == 0 {
+			c[i] = 1
+		}
+	}
+	return c
+}
+
+// jumpingOnClouds' greedy path packed into bits, bit k is jump k: 0 = one step,
+// 1 = two. the jump count is jumpingOnClouds(c), only the first 64 jumps fit
+func JumpingOnCloudsEncoded(c []int32) uint64 {
+	encoded := uint64(0)
+	n := len(c)
+	for i, k := 0, 0; i < n-1; k++ {
+		if i+2 < n && c[i+2] != 1 {
+			if k < 64 {
+				encoded |= 1 << k
+			}
+			i += 2
+		} else {
+			i += 1
+		}
+	}
+	return encoded
+}
+
+// the step sizes (1 or 2) of the first `jumps` jumps in an encoded path, jumps up to 64
+func DecodeCloudPath(encoded uint64, jumps int) []int {
+	steps := make([]int, 0, max(0, min(jumps, 64)))
+	for k := 0; k < jumps && k < 64; k++ {
+		steps = append(steps, 1+int(encoded>>k&1))
+	}
+	return steps
+}
+
+// every layout of length n randomClouds could produce: both ends safe, no two
+// thunderheads in a row. fib(n) of them (1, 1, 2, 3, 5, ... for n = 1, 2, 3, ...),
+// so only for small n
+func EnumerateValidClouds(n int) [][]int32 {
+	layouts := [][]int32{}
+	if n < 1 {
+		return layouts
+	}
+	c := make([]int32, n)
+	var fill func(i int)
+	fill = func(i int) {
+		if i >= n-1 {
+			layouts = append(layouts, append([]int32{}, c...))
+			return
+		}
+		c[i] = 0
+		fill(i + 1)
+		if c[i-1] == 0 {
+			c[i] = 1
+			fill(i + 1)
+			c[i] = 0
+		}
+	}
+	fill(1)
+	return layouts
+}
+
+// tries turning each thunderhead safe on its own, returns the ones that save the most
+// jumps (all of them on a tie, ascending) and the jump count after flipping any one.
+// nothing saves a jump -> empty and the current count
+func CloudsToFlipForFewerJumps(c []int32) ([]int, int32) {
+	best := jumpingOnClouds(c)
+	flips := []int{}
+	trial := append([]int32{}, c...)
+	for i, cloud := range c {
+		if cloud != 1 {
+			continue
+		}
+		trial[i] = 0
+		jumps := jumpingOnClouds(trial)
+		trial[i] = 1
+		switch {
+		case jumps < best:
+			best, flips = jumps, []int{i}
+		case jumps == best && len(flips) > 0:
+			flips = append(flips, i)
+		}
+	}
+	return flips, best
+}
+
+// how many different sequences of 1 steps and 2 jumps get from the first cloud to the
+// last without touching a thunderhead. ways[i] = ways[i-1] + ways[i-2] on safe clouds,
+// Fibonacci when there are none, so it stops at math.MaxInt64 instead of wrapping
+func CountCloudPaths(c []int32) int64 {
+	n := len(c)
+	if n == 0 || c[0] == 1 {
+		return 0
+	}
+	ways := make([]int64, n)
+	ways[0] = 1
+	for i := 1; i < n; i++ {
+		if c[i] == 1 {
+			continue
+		}
+		ways[i] = ways[i-1]
+		if i >= 2 {
+			if ways[i-2] > math.MaxInt64-ways[i] {
+				ways[i] = math.MaxInt64
+			} else {
+				ways[i] += ways[i-2]
+			}
+		}
+	}
+	return ways[n-1]
+}
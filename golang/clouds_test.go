@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// reference for jumpingOnClouds, explores every reachable cloud level by level
+// so the first time we hit the last cloud we have the true minimum
+func jumpingOnCloudsBFS(c []int32) int32 {
+	n := len(c)
+	if n <= 1 {
+		return 0
+	}
+	dist := make([]int32, n) // dist[i] = min jumps to reach cloud i, -1 = not seen
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[0] = 0
+	queue := []int{0}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, step := range []int{1, 2} {
+			next := i + step
+			if next >= n || c[next] == 1 || dist[next] != -1 {
+				continue
+			}
+			dist[next] = dist[i] + 1
+			if next == n-1 {
+				return dist[next]
+			}
+			queue = append(queue, next)
+		}
+	}
+	return -1 // last cloud not reachable
+}
+
+func TestJumpingOnCloudsMatchesBFS(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 2000; trial++ {
+		c := randomClouds(rng, 2+rng.Intn(40))
+		if got, want := jumpingOnClouds(c), jumpingOnCloudsBFS(c); got != want {
+			t.Fatalf("jumpingOnClouds(%v) = %d, BFS says %d", c, got, want)
+		}
+	}
+}
+
+func TestJumpingOnCloudsScore(t *testing.T) {
+	cases := []struct {
+		c                  []int32
+		stepCost, jumpCost int
+		want               int
+	}{
+		{[]int32{0, 0, 0, 0, 0}, 1, 1, 2}, // two jumps, the min-jump route
+		{[]int32{0, 0, 0, 0, 0}, 1, 3, 4}, // four steps (4) beat the two jumps (6)
+		{[]int32{0, 0, 1, 0, 0}, 1, 5, 7}, // the jump over the thunderhead can't be avoided
+		{[]int32{0, 1, 1, 0}, 1, 1, -1},
+		{[]int32{0}, 1, 1, 0},
+	}
+	for _, c := range cases {
+		if got := JumpingOnCloudsScore(c.c, c.stepCost, c.jumpCost); got != c.want {
+			t.Errorf("JumpingOnCloudsScore(%v, %d, %d) = %d, want %d", c.c, c.stepCost, c.jumpCost, got, c.want)
+		}
+	}
+	// with both costs 1 it's the jump count
+	rng := rand.New(rand.NewSource(2))
+	for trial := 0; trial < 500; trial++ {
+		c := randomClouds(rng, 1+rng.Intn(30))
+		if got, want := JumpingOnCloudsScore(c, 1, 1), int(jumpingOnClouds(c)); got != want {
+			t.Fatalf("JumpingOnCloudsScore(%v, 1, 1) = %d, jumpingOnClouds says %d", c, got, want)
+		}
+	}
+}
+
+func TestCloudSegments(t *testing.T) {
+	cases := []struct {
+		c    []int32
+		want [][2]int
+	}{
+		{[]int32{0, 0, 1, 0, 0, 0}, [][2]int{{0, 1}, {3, 5}}}, // two runs split by a thunderhead
+		{[]int32{0, 1, 0, 1, 0}, [][2]int{{0, 0}, {2, 2}, {4, 4}}},
+		{[]int32{0, 0, 0}, [][2]int{{0, 2}}},
+		{[]int32{}, [][2]int{}},
+	}
+	for _, c := range cases {
+		if got := CloudSegments(c.c); !slices.Equal(got, c.want) {
+			t.Errorf("CloudSegments(%v) = %v, want %v", c.c, got, c.want)
+		}
+	}
+}
+
+func TestJumpCountDistribution(t *testing.T) {
+	const n, trials = 20, 500
+	counts := JumpCountDistribution(n, trials, 42)
+	total := 0
+	for jumps, c := range counts {
+		total += c
+		if jumps < n/2 || jumps > n-1 { // (n-1)/2 rounded up is n/2
+			t.Errorf("%d layouts took %d jumps, outside %d..%d", c, jumps, n/2, n-1)
+		}
+	}
+	if total != trials {
+		t.Errorf("counts add up to %d, want %d", total, trials)
+	}
+	if again := JumpCountDistribution(n, trials, 42); !maps.Equal(again, counts) {
+		t.Errorf("same seed gave %v then %v", counts, again)
+	}
+	if got := JumpCountDistribution(1, 10, 1); !maps.Equal(got, map[int32]int{0: 10}) {
+		t.Errorf("single cloud: %v, want 10 layouts with 0 jumps", got)
+	}
+}
+
+func TestJumpingOnCloudsEncoded(t *testing.T) {
+	if got, want := DecodeCloudPath(JumpingOnCloudsEncoded([]int32{0, 0, 1, 0, 0, 1, 0}), 4), []int{1, 2, 1, 2}; !slices.Equal(got, want) {
+		t.Errorf("sample path = %v, want %v", got, want)
+	}
+
+	rng := rand.New(rand.NewSource(5))
+	for trial := 0; trial < 500; trial++ {
+		c := randomClouds(rng, 2+rng.Intn(60)) // up to 59 jumps, all fit
+		jumps := int(jumpingOnClouds(c))
+		steps := DecodeCloudPath(JumpingOnCloudsEncoded(c), jumps)
+		i := 0
+		for _, step := range steps { // walking it only lands on safe clouds
+			i += step
+			if i >= len(c) || c[i] == 1 {
+				t.Fatalf("%v: path %v lands on %d", c, steps, i)
+			}
+		}
+		if len(steps) != jumps || i != len(c)-1 {
+			t.Fatalf("%v: path %v ends on %d after %d jumps, want %d after %d", c, steps, i, len(steps), len(c)-1, jumps)
+		}
+	}
+
+	if got := DecodeCloudPath(^uint64(0), 70); len(got) != 64 {
+		t.Errorf("decoding 70 jumps gave %d steps, want 64", len(got))
+	}
+}
+
+// every layout up to 13 clouds, so the greedy answer is checked exhaustively
+// instead of on random ones
+func TestEnumerateValidClouds(t *testing.T) {
+	fib := []int{0, 1, 1}
+	for n := 1; n < 14; n++ {
+		for len(fib) <= n {
+			fib = append(fib, fib[len(fib)-1]+fib[len(fib)-2])
+		}
+		layouts := EnumerateValidClouds(n)
+		if len(layouts) != fib[n] {
+			t.Errorf("EnumerateValidClouds(%d) gave %d layouts, want fib(%d) = %d", n, len(layouts), n, fib[n])
+		}
+		seen := map[string]bool{}
+		for _, c := range layouts {
+			key := fmt.Sprint(c)
+			if seen[key] {
+				t.Fatalf("n=%d: %v twice", n, c)
+			}
+			seen[key] = true
+			if len(c) != n || c[0] != 0 || c[n-1] != 0 || strings.Contains(key, "1 1") {
+				t.Fatalf("n=%d: %v isn't a valid layout", n, c)
+			}
+			greedy, bfs, score := jumpingOnClouds(c), jumpingOnCloudsBFS(c), JumpingOnCloudsScore(c, 1, 1)
+			if greedy != bfs || int(greedy) != score {
+				t.Fatalf("%v: greedy %d, BFS %d, JumpingOnCloudsScore %d", c, greedy, bfs, score)
+			}
+		}
+	}
+	if got := EnumerateValidClouds(0); len(got) != 0 {
+		t.Errorf("EnumerateValidClouds(0) = %v, want none", got)
+	}
+}
+
+func TestCloudsToFlipForFewerJumps(t *testing.T) {
+	cases := []struct {
+		c     []int32
+		flips []int
+		jumps int32
+	}{
+		{[]int32{0, 0, 1, 0, 0}, []int{2}, 2}, // 0 1 3 4 becomes 0 2 4
+		{[]int32{0, 1, 0}, []int{}, 1},        // already one jump
+		{[]int32{0, 0, 0, 0}, []int{}, 2},     // nothing to flip
+		{[]int32{0, 0, 1, 0, 0, 1, 0, 0}, []int{2, 5}, 4},
+	}
+	for _, c := range cases {
+		flips, jumps := CloudsToFlipForFewerJumps(c.c)
+		if !slices.Equal(flips, c.flips) || jumps != c.jumps {
+			t.Errorf("CloudsToFlipForFewerJumps(%v) = %v, %d, want %v, %d", c.c, flips, jumps, c.flips, c.jumps)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(6))
+	for trial := 0; trial < 300; trial++ {
+		c := randomClouds(rng, 2+rng.Intn(30))
+		flips, jumps := CloudsToFlipForFewerJumps(c)
+		before := jumpingOnCloudsBFS(c)
+		if len(flips) == 0 && jumps != before || len(flips) > 0 && jumps >= before {
+			t.Fatalf("%v: %v flips for %d jumps, %d before", c, flips, jumps, before)
+		}
+		for i, cloud := range c {
+			if cloud != 1 {
+				continue
+			}
+			trial := slices.Clone(c)
+			trial[i] = 0
+			after := jumpingOnCloudsBFS(trial)
+			if after < jumps || after == jumps && jumps < before && !slices.Contains(flips, i) {
+				t.Fatalf("%v: flipping %d gives %d jumps, CloudsToFlipForFewerJumps said %v, %d", c, i, after, flips, jumps)
+			}
+		}
+	}
+}
+
+func TestCountCloudPaths(t *testing.T) {
+	cases := []struct {
+		c    []int32
+		want int64
+	}{
+		{[]int32{0, 0, 0}, 2},       // 1+1 or 2
+		{[]int32{0, 0, 0, 1, 0}, 2}, // two ways to the cloud before the thunderhead, one jump over it
+		{[]int32{0, 1, 0}, 1},
+		{[]int32{0, 1, 1, 0}, 0},
+		{[]int32{0}, 1},
+		{[]int32{}, 0},
+		{make([]int32, 10), 55},             // fib(10)
+		{make([]int32, 100), math.MaxInt64}, // stops instead of wrapping
+	}
+	for _, c := range cases {
+		if got := CountCloudPaths(c.c); got != c.want {
+			t.Errorf("CountCloudPaths(%v) = %d, want %d", c.c, got, c.want)
+		}
+	}
+
+	var walk func(c []int32, i int) int64 // every path tried one by one
+	walk = func(c []int32, i int) int64 {
+		switch {
+		case i >= len(c) || c[i] == 1:
+			return 0
+		case i == len(c)-1:
+			return 1
+		}
+		return walk(c, i+1) + walk(c, i+2)
+	}
+	rng := rand.New(rand.NewSource(7))
+	for trial := 0; trial < 300; trial++ {
+		c := randomClouds(rng, 1+rng.Intn(20))
+		if got, want := CountCloudPaths(c), walk(c, 0); got != want {
+			t.Fatalf("CountCloudPaths(%v) = %d, walking them all gives %d", c, got, want)
+		}
+	}
+}
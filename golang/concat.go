@@ -0,0 +1,56 @@
+package main
+
+import "strings"
+
+// Concat matches a match of a followed by a match of b. there are no groups, so
+// (a1|a2)(b1|b2) is spelled out as a1b1|a1b2|a2b1|a2b2, in that order so the first
+// alternative to match is still the one a and b would pick on their own.
+// the backtracker runs over the joined token list, so a star at the end of a gives
+// back to b like any other star: Concat(`*a`, `bc`) matches "aabc".
+// both should be compiled the same way, the result takes a's options, and a group
+// name shouldn't be used in both
+func Concat(a, b *Pattern) *Pattern {
+	srcA, srcB := splitAlternatives(a.src), splitAlternatives(b.src)
+	out := &Pattern{byteMode: a.byteMode, predicates: a.predicates, iterative: a.iterative}
+	srcs := []string{}
+	for i, ta := range a.alts {
+		for j, tb := range b.alts {
+			out.alts = append(out.alts, append(append([]token{}, ta...), tb...))
+			if len(srcA) == len(a.alts) && len(srcB) == len(b.alts) {
+				srcs = append(srcs, srcA[i]+srcB[j])
+			}
+		}
+	}
+	if len(srcs) == len(out.alts) {
+		out.src = strings.Join(srcs, "|")
+	} else {
+		out.src = a.src + b.src // only if a src was cut wrong, keep something readable
+	}
+	return out
+}
+
+// src cut at its top level `|`s, skipping escapes, classes and \p{name}
+func splitAlternatives(src string) []string {
+	parts := []string{}
+	start := 0
+	for i := 0; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			if strings.HasPrefix(src[i+1:], "p{") {
+				if close := strings.IndexByte(src[i:], '}'); close != -1 {
+					i += close
+					continue
+				}
+			}
+			i++ // escaped char, multibyte ones have no `|` bytes inside anyway
+		case '[':
+			if close := strings.IndexByte(src[i+1:], ']'); close != -1 {
+				i += 1 + close
+			}
+		case '|':
+			parts = append(parts, src[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, src[start:])
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestConcat(t *testing.T) {
+	cases := []struct {
+		a, b, s string
+		want    bool
+	}{
+		{"*a", "bc", "aabc", true},
+		{"*a", "abc", "aabc", true}, // the star gives back an a
+		{"*a", "bc", "bc", false},
+		{"a|b", "x|y", "by", true},
+		{"a|b", "x|y", "xy", false},
+		{"a?", "b", "b", true},
+		{"{1,2}a", ".", "aaa", true},
+	}
+	for _, c := range cases {
+		p := Concat(MustCompile(c.a), MustCompile(c.b))
+		if got := p.Match(c.s); got != c.want {
+			t.Errorf("Concat(%q, %q).Match(%q) = %v, want %v", c.a, c.b, c.s, got, c.want)
+		}
+	}
+	if src := Concat(MustCompile("a|b"), MustCompile("x|y")).src; src != "ax|ay|bx|by" {
+		t.Errorf("Concat(a|b, x|y) src = %q, want %q", src, "ax|ay|bx|by")
+	}
+}
+
+// without possessive repeats the backtracker gives back whatever b needs, so a
+// match of a followed by a match of b is always a match of Concat(a, b)
+func TestConcatMatchesPieces(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+	for trial := 0; trial < 500; trial++ {
+		a, b := MustCompile(randomPattern(rng, false)), MustCompile(randomPattern(rng, false))
+		p := Concat(a, b)
+		for k := 0; k < 20; k++ {
+			x, y := randomInput(rng, "abc", 4), randomInput(rng, "abc", 4)
+			if a.Match(x) && b.Match(y) && !p.Match(x+y) {
+				t.Fatalf("Concat(%q, %q) doesn't match %q+%q", a.src, b.src, x, y)
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// same shape as concurrentTask, but instead of printing, the start/finish
+// lines are appended under the mutex so the slice is in order of occurrence
+func ConcurrentTaskLog(tasks []int, concurrency int) []string {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	logs := make([]string, 0, 2*len(tasks))
+
+	throttle := NewThrottle(concurrency)
+	for _, t := range tasks {
+		wg.Add(1)
+		throttle.Acquire(context.Background()) // never cancelled, can't fail
+		go func(task int) {
+			defer wg.Done()
+			defer throttle.Release()
+
+			mu.Lock()
+			logs = append(logs, fmt.Sprintf("start %d", task))
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond) // pretend to work
+
+			mu.Lock()
+			logs = append(logs, fmt.Sprintf("finish %d", task))
+			mu.Unlock()
+		}(t)
+	}
+
+	wg.Wait()
+	return logs
+}
+
+// Throttle is the sem channel from concurrentTask pulled out so it can be reused,
+// at most cap(slots) holders at once
+type Throttle struct {
+	slots chan struct{}
+}
+
+func NewThrottle(limit int) *Throttle {
+	if limit < 1 {
+		limit = 1
+	}
+	return &Throttle{slots: make(chan struct{}, limit)}
+}
+
+// blocks until a slot is free, or returns ctx.Err() if ctx is done first
+func (t *Throttle) Acquire(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err // don't grab a slot for an already cancelled ctx
+	}
+	select {
+	case t.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// hands a slot back, only call after a successful Acquire
+func (t *Throttle) Release() {
+	<-t.slots
+}
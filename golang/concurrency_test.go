@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestConcurrentTaskLogRespectsCap(t *testing.T) {
+	tasks := []int{}
+	for i := 0; i < 12; i++ {
+		tasks = append(tasks, i)
+	}
+	for _, concurrency := range []int{1, 3, 5} {
+		logs := ConcurrentTaskLog(tasks, concurrency)
+		if len(logs) != 2*len(tasks) {
+			t.Fatalf("concurrency %d: %d log lines, want %d", concurrency, len(logs), 2*len(tasks))
+		}
+		running := map[int]bool{}
+		done := map[int]bool{}
+		for _, line := range logs {
+			var event string
+			var task int
+			if _, err := fmt.Sscanf(line, "%s %d", &event, &task); err != nil {
+				t.Fatalf("bad log line %q: %v", line, err)
+			}
+			switch event {
+			case "start":
+				if running[task] || done[task] {
+					t.Fatalf("task %d started twice", task)
+				}
+				running[task] = true
+				if len(running) > concurrency {
+					t.Fatalf("concurrency %d: %d tasks running at once", concurrency, len(running))
+				}
+			case "finish":
+				if !running[task] {
+					t.Fatalf("task %d finished without starting", task)
+				}
+				delete(running, task)
+				done[task] = true
+			default:
+				t.Fatalf("bad log line %q", line)
+			}
+		}
+		if len(done) != len(tasks) {
+			t.Errorf("concurrency %d: %d tasks finished, want %d", concurrency, len(done), len(tasks))
+		}
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	throttle := NewThrottle(2)
+	for i := 0; i < 2; i++ {
+		if err := throttle.Acquire(context.Background()); err != nil {
+			t.Fatalf("acquire %d of 2: %v", i+1, err)
+		}
+	}
+
+	// full, the next Acquire has to wait for a Release
+	acquired := make(chan error, 1)
+	go func() { acquired <- throttle.Acquire(context.Background()) }()
+	select {
+	case err := <-acquired:
+		t.Fatalf("third Acquire returned %v while both slots were held", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	throttle.Release()
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("third Acquire after a Release: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("third Acquire still blocked after a Release")
+	}
+
+	// full again, a blocked Acquire gives up when its ctx is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { acquired <- throttle.Acquire(ctx) }()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-acquired:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("cancelled Acquire = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("cancelled Acquire still blocked")
+	}
+
+	// the cancelled one took no slot: two Releases free both
+	throttle.Release()
+	throttle.Release()
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err := throttle.Acquire(ctx)
+		cancel()
+		if err != nil {
+			t.Fatalf("acquire %d after releasing everything: %v", i+1, err)
+		}
+	}
+
+	// an already cancelled ctx fails without taking a slot even when one is free
+	throttle.Release()
+	if err := throttle.Acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Acquire with a done ctx = %v, want context.Canceled", err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := throttle.Acquire(ctx); err != nil {
+		t.Fatalf("free slot taken by the cancelled Acquire: %v", err)
+	}
+}
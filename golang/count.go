@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// CountMatches is how many distinct strings the pattern matches, over an alphabet of
+// alphabetSize chars made of every char the pattern names (literals, class members)
+// plus unnamed ones to fill it up. `..` with 26 is 676, `[^a]` with 26 is 25.
+// strings matched several ways (`a?a?`, `a|a`) count once.
+// ok is false when there's no finite answer to give: an unbounded repeat, a predicate
+// (its chars can't be listed), a possessive repeat, or a count past int64
+func (p *Pattern) CountMatches(alphabetSize int) (count int64, ok bool) {
+	for _, tokens := range p.alts {
+		for i := range tokens {
+			if t := &tokens[i]; t.max == -1 || t.kind == tokPredicate || t.possessive {
+				return 0, false
+			}
+		}
+	}
+
+	// the same stretches DisjointPatterns steps over, each weighted by how many
+	// alphabet chars it stands for. the unnamed chars all act alike, one rune stands in
+	reps, weights := []rune{}, []int64{}
+	points := distinguishingRunes(p)
+	named := int64(0)
+	other := rune(-1)
+	for i, lo := range points {
+		hi := rune(math.MaxInt32)
+		if i+1 < len(points) {
+			hi = points[i+1] - 1
+		}
+		if !p.names(lo) {
+			if other == -1 {
+				other = lo
+			}
+			continue
+		}
+		reps = append(reps, lo)
+		weights = append(weights, int64(hi-lo+1))
+		named += int64(hi - lo + 1)
+	}
+	if rest := int64(alphabetSize) - named; rest > 0 && other != -1 {
+		reps = append(reps, other)
+		weights = append(weights, rest)
+	}
+
+	prog := compileNFA(p.alts)
+	memo := map[string]int64{}
+	overflow := false
+	// distinct strings still reachable from the state set, bounded repeats only
+	// so the NFA has no loops and this bottoms out
+	var distinct func(pcs []int) int64
+	distinct = func(pcs []int) int64 {
+		key := fmt.Sprint(pcs)
+		if n, seen := memo[key]; seen {
+			return n
+		}
+		n := int64(0)
+		if prog.accepts(pcs) {
+			n = 1
+		}
+		for k, r := range reps {
+			next := prog.step(pcs, r)
+			if len(next) == 0 {
+				continue
+			}
+			sub := distinct(next)
+			if sub > 0 && weights[k] > (math.MaxInt64-n)/sub {
+				overflow = true
+				continue
+			}
+			n += weights[k] * sub
+		}
+		memo[key] = n
+		return n
+	}
+	count = distinct(prog.closure([]int{prog.start}))
+	if overflow {
+		return 0, false
+	}
+	return count, true
+}
+
+// does some literal or class in p mention r
+func (p *Pattern) names(r rune) bool {
+	for _, tokens := range p.alts {
+		for i := range tokens {
+			t := &tokens[i]
+			switch t.kind {
+			case tokLiteral:
+				if t.r == r {
+					return true
+				}
+			case tokClass:
+				for _, rg := range t.class.ranges {
+					if rg[0] <= r && r <= rg[1] {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
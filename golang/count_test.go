@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestCountMatches(t *testing.T) {
+	cases := []struct {
+		pattern string
+		size    int
+		count   int64
+		ok      bool
+	}{
+		{"..", 26, 676, true},
+		{"[^a]", 26, 25, true},
+		{"abc", 26, 1, true},
+		{"a?a?", 26, 3, true}, // "", "a", "aa"
+		{"a|a", 26, 1, true},
+		{"{1,3}[ab]", 2, 14, true},
+		{"a*b", 26, 0, false},
+		{"{2,}a", 26, 0, false},
+		{"{2}+a", 26, 0, false},
+		{"{40}.", 26, 0, false}, // 26^40 is past int64
+	}
+	for _, c := range cases {
+		count, ok := MustCompile(c.pattern).CountMatches(c.size)
+		if count != c.count || ok != c.ok {
+			t.Errorf("%q.CountMatches(%d) = %d, %v, want %d, %v", c.pattern, c.size, count, ok, c.count, c.ok)
+		}
+	}
+}
+
+// counted against every string over "abc": the patterns only name a and b, so c
+// stands for the one unnamed char an alphabet of 3 has
+func TestCountMatchesBruteForce(t *testing.T) {
+	atoms := []string{"a", "b", ".", "[ab]", "[^a]"}
+	rng := rand.New(rand.NewSource(9))
+	for trial := 0; trial < 200; trial++ {
+		src := ""
+		for n := 1 + rng.Intn(3); n > 0; n-- {
+			switch rng.Intn(3) {
+			case 0:
+				src += fmt.Sprintf("{%d,%d}", rng.Intn(2), 1+rng.Intn(2))
+			case 1:
+				src += atoms[rng.Intn(len(atoms))] + "?"
+				continue
+			}
+			src += atoms[rng.Intn(len(atoms))]
+		}
+		p, err := Compile(src)
+		if err != nil {
+			continue
+		}
+		strs := []string{""}
+		want := int64(0)
+		for len(strs) > 0 {
+			s := strs[0]
+			strs = strs[1:]
+			if p.Match(s) {
+				want++
+			}
+			if len(s) < 6 {
+				strs = append(strs, s+"a", s+"b", s+"c")
+			}
+		}
+		if got, ok := p.CountMatches(3); !ok || got != want {
+			t.Errorf("%q.CountMatches(3) = %d, %v, want %d, true", src, got, ok, want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Debug dumps the compiled tokens one per line, e.g. for `a*bc.z`
+//
+//	literal 'a'
+//	literal 'b' x1..inf
+//	literal 'c'
+//	any
+//	literal 'z'
+//
+// with more than one alternative each gets an `alt N` header and its tokens indented
+func (p *Pattern) Debug() string {
+	var sb strings.Builder
+	indent := ""
+	for a, tokens := range p.alts {
+		if len(p.alts) > 1 {
+			fmt.Fprintf(&sb, "alt %d\n", a)
+			indent = "  "
+		}
+		if len(tokens) == 0 {
+			sb.WriteString(indent + "empty\n")
+		}
+		for i := range tokens {
+			sb.WriteString(indent + tokens[i].describe() + "\n")
+		}
+	}
+	return sb.String()
+}
+
+func (t *token) describe() string {
+	var desc string
+	switch t.kind {
+	case tokAny:
+		desc = "any"
+	case tokClass:
+		desc = "class " + t.class.String()
+	case tokPredicate:
+		desc = "predicate " + t.name
+	default:
+		desc = fmt.Sprintf("literal %q", t.r)
+	}
+	if t.possessive {
+		desc += " possessive"
+	}
+	switch {
+	case t.min == 1 && t.max == 1:
+	case t.max == -1:
+		desc = fmt.Sprintf("%s x%d..inf", desc, t.min)
+	default:
+		desc = fmt.Sprintf("%s x%d..%d", desc, t.min, t.max)
+	}
+	if t.group != "" {
+		desc += " in " + t.group
+	}
+	return desc
+}
+
+// back to `[...]` syntax
+func (c *charClass) String() string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	if c.negate {
+		sb.WriteByte('^')
+	}
+	dash := false
+	for _, rg := range c.ranges {
+		if rg == [2]rune{'-', '-'} {
+			dash = true // written last, [x-y] means something else than x, -, y
+			continue
+		}
+		sb.WriteRune(rg[0])
+		if rg[1] != rg[0] {
+			sb.WriteByte('-')
+			sb.WriteRune(rg[1])
+		}
+	}
+	if dash {
+		sb.WriteByte('-')
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestDebug(t *testing.T) {
+	cases := []struct {
+		pattern, want string
+	}{
+		{"a*bc.z", "literal 'a'\nliteral 'b' x1..inf\nliteral 'c'\nany\nliteral 'z'\n"},
+		{"{2,3}[^a-c]x?", "class [^a-c] x2..3\nliteral 'x' x0..1\n"},
+		{"a|", "alt 0\n  literal 'a'\nalt 1\n  empty\n"},
+		{"*+a(?<n>b)", "literal 'a' possessive x1..inf\nliteral 'b' in n\n"},
+	}
+	for _, c := range cases {
+		if got := MustCompile(c.pattern).Debug(); got != c.want {
+			t.Errorf("%q.Debug() =\n%s\nwant\n%s", c.pattern, got, c.want)
+		}
+	}
+}
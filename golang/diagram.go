@@ -0,0 +1,36 @@
+package main
+
+// DiagramNode is one box of a railroad diagram, the boxes of one alternative run left
+// to right and each alternative is its own track
+type DiagramNode struct {
+	Alt        int    // which `|` alternative (track) the node sits on
+	Kind       string // "literal", "any", "class" or "predicate"
+	Label      string // text for the box: the char, ".", "[a-z]" or the predicate name
+	Min, Max   int    // how many times round, Max -1 = no limit (draw a loop back)
+	Possessive bool   // the loop never gives back, worth marking on the drawing
+	Group      string // the named group the box is inside, "" for none
+}
+
+// Diagram flattens the tokens into nodes in pattern order, alternative by alternative.
+// `a*bc.z` -> literal a, literal b (1..-1), literal c, any, literal z, all on track 0
+func (p *Pattern) Diagram() []DiagramNode {
+	nodes := []DiagramNode{}
+	for a, tokens := range p.alts {
+		for i := range tokens {
+			t := &tokens[i]
+			node := DiagramNode{Alt: a, Min: t.min, Max: t.max, Possessive: t.possessive, Group: t.group}
+			switch t.kind {
+			case tokAny:
+				node.Kind, node.Label = "any", "."
+			case tokClass:
+				node.Kind, node.Label = "class", t.class.String()
+			case tokPredicate:
+				node.Kind, node.Label = "predicate", t.name
+			default:
+				node.Kind, node.Label = "literal", string(t.r)
+			}
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
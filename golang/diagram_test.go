@@ -0,0 +1,32 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"unicode"
+)
+
+func TestDiagram(t *testing.T) {
+	got := MustCompile("a*bc.z").Diagram()
+	want := []DiagramNode{
+		{Kind: "literal", Label: "a", Min: 1, Max: 1},
+		{Kind: "literal", Label: "b", Min: 1, Max: -1},
+		{Kind: "literal", Label: "c", Min: 1, Max: 1},
+		{Kind: "any", Label: ".", Min: 1, Max: 1},
+		{Kind: "literal", Label: "z", Min: 1, Max: 1},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Diagram(a*bc.z) = %v, want %v", got, want)
+	}
+
+	p := MustCompile(`x?|(?<n>{2,}+[a-z])\p{digit}`, WithPredicates(map[string]func(rune) bool{"digit": unicode.IsDigit}))
+	got = p.Diagram()
+	want = []DiagramNode{
+		{Alt: 0, Kind: "literal", Label: "x", Min: 0, Max: 1},
+		{Alt: 1, Kind: "class", Label: "[a-z]", Min: 2, Max: -1, Possessive: true, Group: "n"},
+		{Alt: 1, Kind: "predicate", Label: "digit", Min: 1, Max: 1},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Diagram(%s) = %v, want %v", p.src, got, want)
+	}
+}
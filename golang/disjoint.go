@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// DisjointPatterns says whether no string of at most maxLen units matches both a and b,
+// false as soon as it finds one that does. both should use the same mode (runes or bytes).
+//
+// it walks both NFAs in step, breadth first, over one rune per stretch of runes the
+// patterns can't tell apart, so it's exact for literals, `.` and classes. a predicate
+// only gets its sample rune tried, and possessive patterns have no NFA so every string
+// over those runes is tried with Match, exponential in maxLen
+func DisjointPatterns(a, b *Pattern, maxLen int) bool {
+	both := func(inA, inB bool) bool { return inA && inB }
+	return !findWitness(a, b, maxLen, both, true)
+}
+
+// ShadowedBy says whether every string of at most maxLen units that specific matches
+// general matches too, so a rule on specific never gets a say after general.
+// same search and caveats as DisjointPatterns, looking for a string only specific takes
+func ShadowedBy(specific, general *Pattern, maxLen int) bool {
+	onlySpecific := func(inA, inB bool) bool { return inA && !inB }
+	return !findWitness(specific, general, maxLen, onlySpecific, false)
+}
+
+// is there a string of at most maxLen units with hit(a matches it, b matches it).
+// needB: hit wants b to match, so strings b can no longer match are dropped early
+func findWitness(a, b *Pattern, maxLen int, hit func(inA, inB bool) bool, needB bool) bool {
+	alphabet := distinguishingRunes(a, b)
+	if a.hasPossessive() || b.hasPossessive() {
+		return witnessBrute(a, b, alphabet, maxLen, hit)
+	}
+	pa, pb := compileNFA(a.alts), compileNFA(b.alts)
+
+	type pair struct{ a, b []int }
+	frontier := []pair{{pa.closure([]int{pa.start}), pb.closure([]int{pb.start})}}
+	seen := map[string]bool{}
+	for n := 0; n <= maxLen && len(frontier) > 0; n++ {
+		next := []pair{}
+		for _, st := range frontier {
+			if hit(pa.accepts(st.a), pb.accepts(st.b)) {
+				return true
+			}
+			for _, r := range alphabet {
+				na, nb := pa.step(st.a, r), pb.step(st.b, r)
+				if len(na) == 0 || needB && len(nb) == 0 {
+					continue // a (or the b hit needs) is dead, nothing down here counts
+				}
+				key := fmt.Sprint(na, nb)
+				if !seen[key] {
+					seen[key] = true
+					next = append(next, pair{na, nb})
+				}
+			}
+		}
+		frontier = next
+	}
+	return false
+}
+
+// one rune per stretch no token of either pattern splits: every literal on its own,
+// every class range edge starts a new stretch, plus the sample of each predicate
+func distinguishingRunes(pats ...*Pattern) []rune {
+	points := []rune{0}
+	for _, p := range pats {
+		for _, tokens := range p.alts {
+			for i := range tokens {
+				t := &tokens[i]
+				switch t.kind {
+				case tokLiteral:
+					points = append(points, t.r, t.r+1)
+				case tokClass:
+					for _, rg := range t.class.ranges {
+						points = append(points, rg[0], rg[1]+1)
+					}
+				case tokPredicate:
+					points = append(points, t.sample())
+				}
+			}
+		}
+	}
+	slices.Sort(points)
+	return slices.Compact(points)
+}
+
+// every string over alphabet up to maxLen, shorter ones before their extensions
+func witnessBrute(a, b *Pattern, alphabet []rune, maxLen int, hit func(inA, inB bool) bool) bool {
+	cur := []rune{}
+	var try func() bool
+	try = func() bool {
+		s := unitString(cur, a.byteMode)
+		if hit(a.Match(s), b.Match(s)) {
+			return true
+		}
+		if len(cur) == maxLen {
+			return false
+		}
+		for _, r := range alphabet {
+			cur = append(cur, r)
+			found := try()
+			cur = cur[:len(cur)-1]
+			if found {
+				return true
+			}
+		}
+		return false
+	}
+	return try()
+}
+
+// back from units to a string, a unit is one byte in byte mode
+func unitString(units []rune, byteMode bool) string {
+	if !byteMode {
+		return string(units)
+	}
+	b := make([]byte, len(units))
+	for i, u := range units {
+		b[i] = byte(u)
+	}
+	return string(b)
+}
+
+// pcs plus everything reachable through splits and jumps, sorted so equal sets compare equal
+func (prog *nfaProg) closure(pcs []int) []int {
+	seen := map[int]bool{}
+	out := []int{}
+	var add func(pc int)
+	add = func(pc int) {
+		if seen[pc] {
+			return
+		}
+		seen[pc] = true
+		switch prog.insts[pc].op {
+		case instJmp:
+			add(prog.insts[pc].x)
+		case instSplit:
+			add(prog.insts[pc].x)
+			add(prog.insts[pc].y)
+		default:
+			out = append(out, pc)
+		}
+	}
+	for _, pc := range pcs {
+		add(pc)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// the states after reading r from pcs
+func (prog *nfaProg) step(pcs []int, r rune) []int {
+	next := []int{}
+	for _, pc := range pcs {
+		if prog.insts[pc].op == instChar && prog.insts[pc].tok.matches(r) {
+			next = append(next, pc+1)
+		}
+	}
+	return prog.closure(next)
+}
+
+func (prog *nfaProg) accepts(pcs []int) bool {
+	for _, pc := range pcs {
+		if prog.insts[pc].op == instMatch {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestDisjointPatterns(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		disjoint bool
+	}{
+		{"*a", "*b", true},
+		{"a.c", "ab?", true}, // lengths never meet
+		{"[a-c]x", "[d-f]x", true},
+		{"a.c", "*[a-c]", false}, // "abc"
+		{"*a", "a?a", false},
+		{"x|*a", "b|aa", false},
+		{"*+a", "aa", false}, // possessive, checked by brute force
+		{"*+aa", "aa", true},
+	}
+	for _, c := range cases {
+		if got := DisjointPatterns(MustCompile(c.a), MustCompile(c.b), 6); got != c.disjoint {
+			t.Errorf("DisjointPatterns(%q, %q) = %v, want %v", c.a, c.b, got, c.disjoint)
+		}
+	}
+}
+
+func TestShadowedBy(t *testing.T) {
+	cases := []struct {
+		specific, general string
+		shadowed          bool
+	}{
+		{"abc", "a.c", true},
+		{"abc", "axc", false},
+		{"a.c", "abc", false},
+		{"[a-c]", "[a-z]", true},
+		{"*a", "*[ab]", true},
+		{"*a", "{1,3}a", false}, // "aaaa" is past the bound
+		{"a|b", "b|a", true},
+		{"aa", "*+a", true},
+	}
+	for _, c := range cases {
+		if got := ShadowedBy(MustCompile(c.specific), MustCompile(c.general), 6); got != c.shadowed {
+			t.Errorf("ShadowedBy(%q, %q) = %v, want %v", c.specific, c.general, got, c.shadowed)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+// DistanceToMatch is the fewest single char edits (insert, delete, substitute) that turn
+// s into something the pattern matches, 0 when it already does. `a.c` on "abx" is 1.
+// shortest path over (NFA state, position in s) with 0/1 costs, so
+// O(len(s) * pattern size) even with stars. possessive repeats are measured as if
+// they gave back like ordinary ones, the NFA has no way to say otherwise
+func (p *Pattern) DistanceToMatch(s string) int {
+	in := p.units(s)
+	prog := compileNFA(p.alts)
+	width := len(in) + 1
+	done := make([]bool, len(prog.insts)*width) // done[pc*width+i], cheapest cost known
+
+	// states by cost: free moves stay in the bucket being worked on, edits go to the next
+	type state struct{ pc, i int }
+	cur := []state{}
+	for _, pc := range prog.closure([]int{prog.start}) {
+		cur = append(cur, state{pc, 0})
+	}
+	for d := 0; len(cur) > 0; d++ {
+		next := []state{}
+		for len(cur) > 0 {
+			st := cur[len(cur)-1]
+			cur = cur[:len(cur)-1]
+			if done[st.pc*width+st.i] {
+				continue
+			}
+			done[st.pc*width+st.i] = true
+
+			inst := prog.insts[st.pc]
+			if inst.op == instMatch && st.i == len(in) {
+				return d
+			}
+			if st.i < len(in) {
+				next = append(next, state{st.pc, st.i + 1}) // delete in[i]
+			}
+			if inst.op != instChar {
+				continue
+			}
+			for _, pc := range prog.closure([]int{st.pc + 1}) {
+				if st.i < len(in) {
+					if inst.tok.matches(in[st.i]) {
+						cur = append(cur, state{pc, st.i + 1})
+					} else {
+						next = append(next, state{pc, st.i + 1}) // substitute
+					}
+				}
+				next = append(next, state{pc, st.i}) // insert a char the token takes
+			}
+		}
+		cur = next
+	}
+	return -1 // can't happen for a compiled pattern, every alternative matches something
+}
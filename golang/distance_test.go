@@ -0,0 +1,61 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDistanceToMatch(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		want       int
+	}{
+		{"a.c", "abx", 1},
+		{"a.c", "abc", 0},
+		{"a.c", "", 3},
+		{"*ab", "b", 1},     // insert an a
+		{"*ab", "aaaxb", 1}, // substitute the x
+		{"abc", "abcabc", 3},
+		{"x|abc", "ab", 1}, // the closer alternative
+		{"[0-9]?z", "9", 1},
+	}
+	for _, c := range cases {
+		if got := MustCompile(c.pattern).DistanceToMatch(c.s); got != c.want {
+			t.Errorf("%q.DistanceToMatch(%q) = %d, want %d", c.pattern, c.s, got, c.want)
+		}
+	}
+}
+
+// for a pattern of plain literals it's the edit distance to that one string
+func TestDistanceToMatchLevenshtein(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	for trial := 0; trial < 1000; trial++ {
+		pattern, s := randomInput(rng, "abc", 6), randomInput(rng, "abc", 6)
+		if pattern == "" {
+			continue
+		}
+		if got, want := MustCompile(pattern).DistanceToMatch(s), levenshtein(pattern, s); got != want {
+			t.Fatalf("%q.DistanceToMatch(%q) = %d, edit distance is %d", pattern, s, got, want)
+		}
+	}
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			sub := prev[j-1]
+			if a[i-1] != b[j-1] {
+				sub++
+			}
+			cur[j] = min(sub, prev[j]+1, cur[j-1]+1)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Equal reports whether both patterns compile to the same canonical tokens.
+// it catches the obvious cases ([a-a] vs a, [ba] vs [ab], b|a vs a|b) but isn't a
+// full language equivalence check, so false doesn't prove they match different strings
+func (p *Pattern) Equal(other *Pattern) bool {
+	return p.canonical() == other.canonical()
+}
+
+// alternatives are sorted and deduped, so their order doesn't matter
+func (p *Pattern) canonical() string {
+	alts := make([]string, 0, len(p.alts))
+	for _, tokens := range p.alts {
+		var sb strings.Builder
+		for i := range tokens {
+			sb.WriteString(tokens[i].canonical())
+		}
+		alts = append(alts, sb.String())
+	}
+	slices.Sort(alts)
+	mode := "rune:"
+	if p.byteMode {
+		mode = "byte:" // same source but a different unit, never equal
+	}
+	return mode + strings.Join(slices.Compact(alts), "|")
+}
+
+func (t *token) canonical() string {
+	key := t.atomKey() + fmt.Sprintf("{%d,%d}", t.min, t.max)
+	if t.possessive {
+		key += "+"
+	}
+	return key
+}
+
+// canonical form of what a single unit has to be, ignoring the repetition
+func (t *token) atomKey() string {
+	switch t.kind {
+	case tokAny:
+		return "."
+	case tokPredicate:
+		return `\p{` + t.name + "}" // same name is assumed to be the same test
+	case tokClass:
+		ranges := t.class.merged()
+		if len(ranges) == 1 && ranges[0][0] == ranges[0][1] && !t.class.negate {
+			return fmt.Sprintf("%q", ranges[0][0]) // [a] is just a
+		}
+		var sb strings.Builder
+		sb.WriteByte('[')
+		if t.class.negate {
+			sb.WriteByte('^')
+		}
+		for _, rg := range ranges {
+			fmt.Fprintf(&sb, "%q-%q", rg[0], rg[1])
+		}
+		sb.WriteByte(']')
+		return sb.String()
+	default:
+		return fmt.Sprintf("%q", t.r)
+	}
+}
+
+// ranges sorted with overlapping / touching ones joined, [c-da-b] -> [a-d]
+func (c *charClass) merged() [][2]rune {
+	ranges := slices.Clone(c.ranges)
+	slices.SortFunc(ranges, func(a, b [2]rune) int { return int(a[0] - b[0]) })
+	out := [][2]rune{}
+	for _, rg := range ranges {
+		if n := len(out); n > 0 && rg[0] <= out[n-1][1]+1 {
+			out[n-1][1] = max(out[n-1][1], rg[1])
+			continue
+		}
+		out = append(out, rg)
+	}
+	return out
+}
@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+func TestPatternEqual(t *testing.T) {
+	equal := [][2]string{
+		{"a*bc", "a*bc"},
+		{"[a-a]", "a"},
+		{"[ba]x", "[ab]x"},
+		{"[c-da-b]", "[a-d]"},
+		{"b|a", "a|b"},
+		{"a|a", "a"},
+		{"a?", "{0,1}a"},
+	}
+	for _, c := range equal {
+		if !MustCompile(c[0]).Equal(MustCompile(c[1])) {
+			t.Errorf("%q.Equal(%q) = false, want true", c[0], c[1])
+		}
+	}
+
+	different := [][2]string{
+		{"abc", "abd"},
+		{"*a", "a"},
+		{"[a-c]", "[^a-c]"},
+		{"*a", "*+a"},
+		{"ab|c", "a|bc"},
+	}
+	for _, c := range different {
+		if MustCompile(c[0]).Equal(MustCompile(c[1])) {
+			t.Errorf("%q.Equal(%q) = true, want false", c[0], c[1])
+		}
+	}
+	if MustCompile("ab").Equal(MustCompile("ab", WithByteMode())) {
+		t.Error("rune and byte mode patterns compared equal")
+	}
+}
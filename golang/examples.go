@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Examples gives the shortest string the pattern matches and, when there is one,
+// the longest. ok is false when a star (or `{m,}`) makes the longest unbounded,
+// maximal is "" then. `.` and classes are shown with a sample rune
+func (p *Pattern) Examples() (minimal, maximal string, ok bool) {
+	ok = true
+	for a, tokens := range p.alts {
+		var lo, hi strings.Builder
+		for i := range tokens {
+			t := &tokens[i]
+			r := string(t.sample())
+			lo.WriteString(strings.Repeat(r, t.min))
+			if t.max == -1 {
+				ok = false
+				continue
+			}
+			hi.WriteString(strings.Repeat(r, t.max))
+		}
+		if a == 0 || lo.Len() < len(minimal) {
+			minimal = lo.String()
+		}
+		if hi.Len() > len(maximal) {
+			maximal = hi.String()
+		}
+	}
+	if !ok {
+		maximal = ""
+	}
+	return minimal, maximal, ok
+}
+
+// some rune the token matches, 'a' when anything goes
+func (t *token) sample() rune {
+	switch t.kind {
+	case tokAny:
+		return 'a'
+	case tokClass:
+		if !t.class.negate {
+			return t.class.merged()[0][0]
+		}
+		// from 'a' up first so the sample reads naturally, then what's below it.
+		// surrogates aren't runes a string can hold, they're skipped
+		for r := 'a'; r <= unicode.MaxRune; r++ {
+			if utf8.ValidRune(r) && t.class.contains(r) {
+				return r
+			}
+		}
+		for r := rune(0); r < 'a'; r++ {
+			if t.class.contains(r) {
+				return r
+			}
+		}
+		return 'a' // the class excludes everything, nothing would match anyway
+	case tokPredicate:
+		for r := rune(0); r < 0x10000; r++ { // a predicate can accept anything, look in the BMP
+			if t.pred(r) {
+				return r
+			}
+		}
+		return 'a'
+	default:
+		return t.r
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestExamples(t *testing.T) {
+	cases := []struct {
+		pattern          string
+		minimal, maximal string
+		ok               bool
+	}{
+		{"a*bc", "abc", "", false},
+		{"abc", "abc", "abc", true},
+		{"a{1,3}b", "ab", "abbb", true},
+		{"x?[b-d].", "ba", "xba", true},
+		{"abcd|{2}x", "xx", "abcd", true},
+		{"{2,}a|b", "b", "", false},
+	}
+	for _, c := range cases {
+		p := MustCompile(c.pattern)
+		minimal, maximal, ok := p.Examples()
+		if minimal != c.minimal || maximal != c.maximal || ok != c.ok {
+			t.Errorf("%q.Examples() = %q, %q, %v, want %q, %q, %v", c.pattern, minimal, maximal, ok, c.minimal, c.maximal, c.ok)
+		}
+		if !p.Match(minimal) || ok && !p.Match(maximal) {
+			t.Errorf("%q doesn't match its own examples %q, %q", c.pattern, minimal, maximal)
+		}
+	}
+}
+
+func TestExamplesNegatedClass(t *testing.T) {
+	cases := []struct {
+		pattern string
+		minimal string
+	}{
+		{"[^a-z]", "{"},                          // the first rune from 'a' up
+		{"[^a-\U0010FFFF]", "\x00"},              // nothing from 'a' up, so from below it
+		{"[^\x00-`b-\U0010FFFF]", "a"},           // just a
+		{"[^\x00-\uD7FF\uE000-\U0010FFFF]", "a"}, // only surrogates left, nothing matches
+	}
+	for _, c := range cases {
+		p := MustCompile(c.pattern)
+		minimal, _, _ := p.Examples()
+		if minimal != c.minimal {
+			t.Errorf("%q.Examples() minimal = %q, want %q", c.pattern, minimal, c.minimal)
+		}
+	}
+}
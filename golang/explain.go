@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Diagnostic is where a match attempt gave up, Index is in units (runes, or bytes in byte mode)
+type Diagnostic struct {
+	Index int
+	Msg   string
+}
+
+// Explain says why s doesn't match: for each alternative, the furthest point into s
+// any backtracking attempt got before failing, deepest first. nil when s matches
+func (p *Pattern) Explain(s string) []Diagnostic {
+	in := p.units(s)
+	diags := []Diagnostic{}
+	for _, tokens := range p.alts {
+		deepest := Diagnostic{Index: -1}
+		m := p.newMatcher(in)
+		m.onFail = func(i int, t *token) {
+			if i <= deepest.Index {
+				return // keep the first failure seen at the deepest point
+			}
+			deepest = Diagnostic{Index: i, Msg: failMessage(in, i, t)}
+		}
+		if m.run(tokens) {
+			return nil
+		}
+		diags = append(diags, deepest)
+	}
+	slices.SortStableFunc(diags, func(a, b Diagnostic) int { return b.Index - a.Index })
+	return diags
+}
+
+func failMessage(in []rune, i int, t *token) string {
+	switch {
+	case t == nil:
+		return fmt.Sprintf("pattern ended but input continues with %q", in[i])
+	case i == len(in):
+		return fmt.Sprintf("input ended, expected %s", t.describe())
+	default:
+		return fmt.Sprintf("expected %s, got %q", t.describe(), in[i])
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		want       []Diagnostic
+	}{
+		{"a*bc.z", "abbbcyq", []Diagnostic{{6, "expected literal 'z', got 'q'"}}}, // a near miss on the last char
+		{"abc", "abcd", []Diagnostic{{3, "pattern ended but input continues with 'd'"}}},
+		{"abcd", "abc", []Diagnostic{{3, "input ended, expected literal 'd'"}}},
+		{"abc|*ax", "aab", []Diagnostic{ // deepest alternative first
+			{2, "expected literal 'x', got 'b'"},
+			{1, "expected literal 'b', got 'a'"},
+		}},
+	}
+	for _, c := range cases {
+		if got := MustCompile(c.pattern).Explain(c.s); !slices.Equal(got, c.want) {
+			t.Errorf("%q.Explain(%q) = %+v, want %+v", c.pattern, c.s, got, c.want)
+		}
+	}
+	if got := MustCompile("a.c").Explain("abc"); got != nil {
+		t.Errorf("Explain on a match = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+// FindIndex is the first place s contains a match, unanchored unlike Match:
+// loc[0]:loc[1] is the byte range in s, nil when nothing matches.
+// leftmost start wins, and from there it's the match MatchPrefix would give
+func (p *Pattern) FindIndex(s string) (loc []int) {
+	all := p.findAll(s, 1)
+	if len(all) == 0 {
+		return nil
+	}
+	return all[0]
+}
+
+// FindAllIndex is every non-overlapping match in s, left to right, each as a byte range
+// like FindIndex. n < 0 means all of them, otherwise at most n
+func (p *Pattern) FindAllIndex(s string, n int) [][]int {
+	return p.findAll(s, n)
+}
+
+func (p *Pattern) findAll(s string, n int) [][]int {
+	in := p.units(s)
+	offs := unitOffsets(s, len(in), p.byteMode)
+	res := [][]int{}
+	for start := 0; start <= len(in) && (n < 0 || len(res) < n); {
+		m := p.newMatcher(in[start:])
+		m.prefix = true
+		found := false
+		for _, tokens := range p.alts {
+			if m.run(tokens) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			start++
+			continue
+		}
+		res = append(res, []int{offs[start], offs[start+m.end]})
+		if m.end == 0 {
+			start++ // empty match, step past it so the loop moves on
+		} else {
+			start += m.end
+		}
+	}
+	return res
+}
+
+// offs[i] = byte offset in s of unit i, offs[count] = len(s)
+func unitOffsets(s string, count int, byteMode bool) []int {
+	offs := make([]int, 0, count+1)
+	if byteMode {
+		for i := 0; i <= len(s); i++ {
+			offs = append(offs, i)
+		}
+		return offs
+	}
+	for i := range s {
+		offs = append(offs, i)
+	}
+	return append(offs, len(s))
+}
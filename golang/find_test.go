@@ -0,0 +1,32 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindIndex(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		want       [][]int
+	}{
+		{"ab", "xabcyabz", [][]int{{1, 3}, {5, 7}}},
+		{"*a", "baaab", [][]int{{1, 4}}},         // greedy from the leftmost start
+		{"é.", "aébéc", [][]int{{1, 4}, {4, 7}}}, // byte offsets, é is two
+		{"x", "abc", [][]int{}},
+	}
+	for _, c := range cases {
+		p := MustCompile(c.pattern)
+		got := p.FindAllIndex(c.s, -1)
+		if !slices.EqualFunc(got, c.want, slices.Equal) {
+			t.Errorf("%q.FindAllIndex(%q) = %v, want %v", c.pattern, c.s, got, c.want)
+		}
+		first := p.FindIndex(c.s)
+		if len(c.want) == 0 && first != nil || len(c.want) > 0 && !slices.Equal(first, c.want[0]) {
+			t.Errorf("%q.FindIndex(%q) = %v, want the first of %v", c.pattern, c.s, first, c.want)
+		}
+	}
+	if got := MustCompile("a").FindAllIndex("aaaa", 2); len(got) != 2 {
+		t.Errorf("FindAllIndex with n=2 gave %d matches", len(got))
+	}
+}
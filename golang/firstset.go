@@ -0,0 +1,33 @@
+package main
+
+import "slices"
+
+// FirstSet is every rune a match could start with, sorted. any=true means a `.`
+// (or a negated class / predicate, which can't be listed) can come first so every
+// rune is possible and the set is meaningless.
+// an input whose first rune isn't in the set can be skipped without matching
+func (p *Pattern) FirstSet() (set []rune, any bool) {
+	set = []rune{}
+	for _, tokens := range p.alts {
+		for i := range tokens {
+			t := &tokens[i]
+			switch {
+			case t.kind == tokAny, t.kind == tokPredicate, t.kind == tokClass && t.class.negate:
+				return nil, true
+			case t.kind == tokClass:
+				for _, rg := range t.class.ranges {
+					for r := rg[0]; r <= rg[1]; r++ {
+						set = append(set, r)
+					}
+				}
+			default:
+				set = append(set, t.r)
+			}
+			if t.min > 0 {
+				break // this token has to be there, nothing after it can come first
+			}
+		}
+	}
+	slices.Sort(set)
+	return slices.Compact(set), false
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFirstSet(t *testing.T) {
+	cases := []struct {
+		pattern string
+		set     []rune
+		any     bool
+	}{
+		{"abc", []rune{'a'}, false},
+		{".bc", nil, true},
+		{"*a|*b", []rune{'a', 'b'}, false},
+		{"a?b?c", []rune{'a', 'b', 'c'}, false}, // optional tokens let the next one start
+		{"[b-d]x", []rune{'b', 'c', 'd'}, false},
+		{"x|[^a]", nil, true},
+	}
+	for _, c := range cases {
+		set, any := MustCompile(c.pattern).FirstSet()
+		if any != c.any || !slices.Equal(set, c.set) {
+			t.Errorf("%q.FirstSet() = %q, %v, want %q, %v", c.pattern, set, any, c.set, c.any)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+// MatchFuzzy is Match where up to maxMismatches units taken by literals may be some
+// other char, `a.c` matches "axd" with 1 (d stands in for c). `.`, classes and
+// predicates still have to match, and so do possessive repeats, which keep all they
+// take as usual. maxMismatches <= 0 is plain Match
+func (p *Pattern) MatchFuzzy(s string, maxMismatches int) bool {
+	if maxMismatches <= 0 {
+		return p.Match(s)
+	}
+	for _, tokens := range p.alts {
+		f := &fuzzyMatcher{in: p.units(s), tokens: tokens, failed: map[[3]int]bool{}}
+		if f.match(0, 0, maxMismatches) {
+			return true
+		}
+	}
+	return false
+}
+
+// backtracker for one alternative with a mismatch budget, failed remembers
+// (token, position, budget) states already known to go nowhere
+type fuzzyMatcher struct {
+	in     []rune
+	tokens []token
+	failed map[[3]int]bool
+}
+
+func (f *fuzzyMatcher) match(ti, i, budget int) bool {
+	if ti == len(f.tokens) {
+		return i == len(f.in)
+	}
+	key := [3]int{ti, i, budget}
+	if f.failed[key] {
+		return false
+	}
+	t := &f.tokens[ti]
+	fuzzy := t.kind == tokLiteral && !t.possessive
+
+	// longest run the token could take, and what each prefix of it costs
+	costs := []int{0} // costs[n] = mismatches in the first n units taken
+	for i+len(costs)-1 < len(f.in) && (t.max == -1 || len(costs)-1 < t.max) {
+		cost := costs[len(costs)-1]
+		if !t.matches(f.in[i+len(costs)-1]) {
+			if !fuzzy || cost == budget {
+				break
+			}
+			cost++
+		}
+		costs = append(costs, cost)
+	}
+	n := len(costs) - 1
+	fewest := t.min
+	if t.possessive {
+		fewest = n
+	}
+	for ; n >= t.min && n >= fewest; n-- {
+		if f.match(ti+1, i+n, budget-costs[n]) {
+			return true
+		}
+	}
+	f.failed[key] = true
+	return false
+}
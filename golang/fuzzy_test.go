@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMatchFuzzy(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		k          int
+		want       bool
+	}{
+		{"a.c", "axd", 1, true}, // d stands in for c
+		{"a.c", "axd", 0, false},
+		{"abc", "xyc", 1, false},
+		{"abc", "xyc", 2, true},
+		{"a[bc]", "xd", 5, false}, // a class still has to match
+		{"*ab", "aaab", 0, true},
+		{"*ab", "abbb", 2, true}, // *a takes "abb", each b it takes a mismatch
+		{"abc", "ab", 3, false},  // mismatches, not missing chars
+	}
+	for _, c := range cases {
+		if got := MustCompile(c.pattern).MatchFuzzy(c.s, c.k); got != c.want {
+			t.Errorf("%q.MatchFuzzy(%q, %d) = %v, want %v", c.pattern, c.s, c.k, got, c.want)
+		}
+	}
+}
+
+// a pattern of plain literals fuzzy matches exactly the strings of its length within
+// k substitutions
+func TestMatchFuzzyHamming(t *testing.T) {
+	rng := rand.New(rand.NewSource(10))
+	for trial := 0; trial < 1000; trial++ {
+		pattern, s := randomInput(rng, "abc", 5), randomInput(rng, "abc", 5)
+		if pattern == "" {
+			continue
+		}
+		k := rng.Intn(4)
+		want := len(pattern) == len(s)
+		if want {
+			diff := 0
+			for i := range s {
+				if s[i] != pattern[i] {
+					diff++
+				}
+			}
+			want = diff <= k
+		}
+		if got := MustCompile(pattern).MatchFuzzy(s, k); got != want {
+			t.Fatalf("%q.MatchFuzzy(%q, %d) = %v, want %v", pattern, s, k, got, want)
+		}
+	}
+}
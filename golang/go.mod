@@ -1,5 +1,3 @@
 module leetcode
 
 go 1.24.1
-
-require github.com/shopspring/decimal v1.4.0
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+// InferPattern builds a simple pattern matching every sample. same length samples
+// keep the positions they all agree on as literals and put `.` where they differ,
+// ["cat","cot","cut"] -> "c.t". different lengths keep the common prefix and suffix
+// and put `*.` (or `{0,}.` if some sample has nothing in between) in the middle.
+// valid, not minimal
+func InferPattern(samples []string) (string, error) {
+	if len(samples) == 0 {
+		return "", errors.New("InferPattern: no samples")
+	}
+	runes := make([][]rune, len(samples))
+	shortest, longest := -1, 0
+	for i, s := range samples {
+		runes[i] = []rune(s)
+		if shortest == -1 || len(runes[i]) < shortest {
+			shortest = len(runes[i])
+		}
+		longest = max(longest, len(runes[i]))
+	}
+
+	var sb strings.Builder
+	if shortest == longest {
+		for pos := 0; pos < shortest; pos++ {
+			if r, same := agreeAt(runes, func(rs []rune) rune { return rs[pos] }); same {
+				writeLiteral(&sb, r)
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		return sb.String(), nil
+	}
+
+	prefix := 0
+	for prefix < shortest {
+		if _, same := agreeAt(runes, func(rs []rune) rune { return rs[prefix] }); !same {
+			break
+		}
+		prefix++
+	}
+	suffix := 0
+	for prefix+suffix < shortest {
+		if _, same := agreeAt(runes, func(rs []rune) rune { return rs[len(rs)-1-suffix] }); !same {
+			break
+		}
+		suffix++
+	}
+	for _, r := range runes[0][:prefix] {
+		writeLiteral(&sb, r)
+	}
+	if prefix+suffix < shortest {
+		sb.WriteString("*.") // every sample has at least one rune in the middle
+	} else {
+		sb.WriteString("{0,}.")
+	}
+	for _, r := range runes[0][len(runes[0])-suffix:] {
+		writeLiteral(&sb, r)
+	}
+	return sb.String(), nil
+}
+
+// whether every sample has the same rune at(sample)
+func agreeAt(runes [][]rune, at func([]rune) rune) (rune, bool) {
+	r := at(runes[0])
+	for _, rs := range runes[1:] {
+		if at(rs) != r {
+			return r, false
+		}
+	}
+	return r, true
+}
+
+// r as a literal, escaped if it means something in the dialect
+func writeLiteral(sb *strings.Builder, r rune) {
+	if strings.ContainsRune(`*+{}?.[]|\()`, r) {
+		sb.WriteByte('\\')
+	}
+	sb.WriteRune(r)
+}
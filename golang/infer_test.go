@@ -0,0 +1,56 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestInferPattern(t *testing.T) {
+	cases := []struct {
+		samples []string
+		want    string
+	}{
+		{[]string{"cat", "cot", "cut"}, "c.t"},
+		{[]string{"abc"}, "abc"},
+		{[]string{"a.c", "a*c"}, "a.c"},
+		{[]string{"log-1.txt", "log-22.txt"}, `log-*.\.txt`}, // a literal dot is escaped
+		{[]string{"ab", "axb"}, "a{0,}.b"},
+	}
+	for _, c := range cases {
+		got, err := InferPattern(c.samples)
+		if err != nil || got != c.want {
+			t.Errorf("InferPattern(%q) = %q, %v, want %q", c.samples, got, err, c.want)
+			continue
+		}
+		p := MustCompile(got)
+		for _, s := range c.samples {
+			if !p.Match(s) {
+				t.Errorf("inferred %q doesn't match sample %q", got, s)
+			}
+		}
+	}
+	if _, err := InferPattern(nil); err == nil {
+		t.Error("InferPattern(nil) returned no error")
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 300; trial++ {
+		samples := make([]string, 1+rng.Intn(4))
+		for i := range samples {
+			samples[i] = randomInput(rng, "ab.*", 5)
+		}
+		got, err := InferPattern(samples)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := Compile(got)
+		if err != nil {
+			t.Fatalf("InferPattern(%q) = %q, which doesn't compile: %v", samples, got, err)
+		}
+		for _, s := range samples {
+			if !p.Match(s) {
+				t.Fatalf("InferPattern(%q) = %q, doesn't match %q", samples, got, s)
+			}
+		}
+	}
+}
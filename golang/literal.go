@@ -0,0 +1,41 @@
+package main
+
+import "strings"
+
+// LiteralPrefix is the plain text every match has to start with, and whether that
+// is the whole pattern (like regexp.Regexp.LiteralPrefix). when complete, matching
+// is just s == literal. with `|` there's no single prefix, so ("", false)
+func (p *Pattern) LiteralPrefix() (literal string, complete bool) {
+	if len(p.alts) != 1 {
+		return "", false
+	}
+	var sb strings.Builder
+	tokens := p.alts[0]
+	for i := range tokens {
+		t := &tokens[i]
+		r, ok := t.literalRune()
+		if !ok || t.min != 1 || t.max != 1 {
+			return sb.String(), false
+		}
+		if p.byteMode {
+			sb.WriteByte(byte(r))
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String(), true
+}
+
+// the one rune a token accepts, ok false if it takes more than one ([a] counts as a)
+func (t *token) literalRune() (r rune, ok bool) {
+	switch t.kind {
+	case tokLiteral:
+		return t.r, true
+	case tokClass:
+		ranges := t.class.merged()
+		if !t.class.negate && len(ranges) == 1 && ranges[0][0] == ranges[0][1] {
+			return ranges[0][0], true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestLiteralPrefix(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		literal  string
+		complete bool
+	}{
+		{"abc", "abc", true},
+		{"ab.c", "ab", false},
+		{"*a", "", false},
+		{"a[b]c", "abc", true},
+		{"ab?c", "a", false},
+		{"ab|ac", "", false},
+		{"", "", true},
+	}
+	for _, c := range cases {
+		literal, complete := MustCompile(c.pattern).LiteralPrefix()
+		if literal != c.literal || complete != c.complete {
+			t.Errorf("%q.LiteralPrefix() = %q, %v, want %q, %v", c.pattern, literal, complete, c.literal, c.complete)
+		}
+	}
+	if literal, complete := MustCompile("é", WithByteMode()).LiteralPrefix(); literal != "é" || !complete {
+		t.Errorf("byte mode é: LiteralPrefix() = %q, %v, want the same two bytes back", literal, complete)
+	}
+}
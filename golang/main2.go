@@ -6,8 +6,6 @@ import (
 	"strings"
 	"sync"
 	"time"
-
-	"github.com/shopspring/decimal"
 )
 
 func main2() {
@@ -74,78 +72,15 @@ func concurrentTask() {
 
 func activityNotifications(expenditure []int32, d int32) int32 {
 	// There are only 201 possible expenditure values (0 to 200),
-	// so we can use a counting sort array to track frequencies
-	const maxVal = 201
-	lenExp := len(expenditure)
+	// so ActivityMonitor keeps a counting sort array of the trailing window
+	// and says for each pushed day whether it's >= 2 × median of the d before it
+	monitor := NewActivityMonitor(d)
 	alerts := int32(0)
-
-	// counts[v] = how many times value v appears in the trailing window of size d
-	counts := make([]int, maxVal)
-
-	// Step 1: Initialize the first window of size `d`
-	// We count the frequency of each expenditure value in the first d days
-	for i := 0; i < int(d); i++ {
-		v := int(expenditure[i])
-		counts[v]++
-	}
-
-	// Step 2: Iterate from day d to the end
-	for i := int(d); i < lenExp; i++ {
-		cum := int32(0)
-		median := float64(0)
-
-		// Step 3: Find the median based on current frequency counts
-		if d%2 == 0 {
-			// For even d, median = average of the two middle numbers
-			target1 := d / 2       // 1st middle position
-			target2 := target1 + 1 // 2nd middle position
-			first := -1
-			second := -1
-
-			// Iterate over all possible expenditure values (0 to 	200)
-			for value, freq := range counts {
-				cum += int32(freq) // accumulate the count
-				// Find the first middle number
-				if first == -1 && cum >= target1 {
-					first = value
-				}
-				// Find the second middle number
-				if cum >= target2 {
-					second = value
-					break // once both found, stop looping
-				}
-			}
-			// Compute median as average of two middle values
-			median = (float64(first) + float64(second)) / 2.0
-
-		} else {
-			// For odd d, median = the middle number
-			target := d/2 + 1
-			for value, freq := range counts {
-				cum += int32(freq)
-				if cum >= target {
-					median = float64(value)
-					break
-				}
-			}
-		}
-
-		// Step 4: Check if today's expenditure >= 2 × median
-		// If yes, raise an alert
-		if float64(expenditure[i]) >= 2*median {
+	for _, spend := range expenditure {
+		if monitor.Push(spend) {
 			alerts++
 		}
-
-		// Step 5: Slide the window:
-		// - Remove the oldest day's expenditure (i-d)
-		// - Add the current day's expenditure (i)
-		oldVal := int(expenditure[i-int(d)])
-		newVal := int(expenditure[i])
-		counts[oldVal]--
-		counts[newVal]++
 	}
-
-	// Step 6: Return total alerts triggered
 	return alerts
 }
 
@@ -153,23 +88,23 @@ func activityNotifications(expenditure []int32, d int32) int32 {
 func activityNotifications2(expenditure []int32, d int32) int32 {
 	expLen := int32(len(expenditure))
 	alert := int32(0)
-	two := decimal.NewFromInt(2)
+	if d <= 0 {
+		return 0 // loop below already skips inputs shorter than d
+	}
 	for i := int32(d); i <= expLen-1; i++ {
-		tempExp := expenditure[i-d : i]
+		tempExp := slices.Clone(expenditure[i-d : i]) // sort a copy, later windows still need the original order
 		slices.Sort(tempExp)
 		tempExpLen := len(tempExp)
-		var median decimal.Decimal
+		// median kept as num/den so the check stays in integers
+		var num, den int64
 		if tempExpLen%2 == 0 {
-			left := decimal.NewFromInt32(tempExp[(tempExpLen/2)-1])
-			right := decimal.NewFromInt32(tempExp[(tempExpLen / 2)])
-			median = left.Add(right).Div(two)
+			num = int64(tempExp[(tempExpLen/2)-1]) + int64(tempExp[(tempExpLen/2)])
+			den = 2
 		} else {
-			midVal := tempExp[tempExpLen/2]
-			median = decimal.NewFromInt32(midVal)
+			num = int64(tempExp[tempExpLen/2])
+			den = 1
 		}
-		currVal := decimal.NewFromInt32(expenditure[i])
-		mul := currVal.Div(median)
-		if mul.GreaterThanOrEqual(two) {
+		if exceedsMedian(expenditure[i], num, den) {
 			alert++
 		}
 	}
@@ -181,20 +116,7 @@ func superReducedString(s string) string {
 	// aaabccddd
 	// baab
 	// cbaabcdde
-	res := "" //
-	for _, char := range s {
-		if res == "" {
-			res = string(char)
-		} else {
-			lastString := string(res[len(res)-1])
-			charS := string(char)
-			if charS == lastString {
-				res = res[:len(res)-1]
-			} else if charS != lastString {
-				res += charS
-			}
-		}
-	}
+	res := SuperReducedStringRaw(s)
 	if res == "" {
 		return "Empty String"
 	} else {
@@ -224,27 +146,32 @@ func jumpingOnClouds(c []int32) int32 {
 func repeatedString(s string, n int64) int64 {
 	// "abcac", 10 => len(S) = 7
 	lenS := int64(len(s)) // 7
+	if lenS == 0 || n <= 0 {
+		return 0 // nothing to repeat, and avoids n % 0
+	}
 	remainder := n % lenS // 3
 	repeat := n / lenS    // 3
-	occurrence := strings.Count(s, "a") * int(repeat)
+
+	// multiply in int64, int is only 32 bits on some platforms
+	occurrence := int64(strings.Count(s, "a")) * repeat
 
 	if remainder > 0 {
 		remainderS := s[0:remainder]
-		occurrence += strings.Count(remainderS, "a")
+		occurrence += int64(strings.Count(remainderS, "a"))
 	}
-	return int64(occurrence)
+	return occurrence
 
 }
 
 // https://www.hackerrank.com/challenges/non-divisible-subset/problem?isFullScreen=true
 func nonDivisibleSubset(s []int32, k int32) int32 {
-	// Step 1: Count remainders
-	freq := make([]int32, k)
-	for _, num := range s {
-		remainder := num % k
-		freq[remainder]++
+	if k <= 0 {
+		return 0 // no such thing as a multiple of 0
 	}
 
+	// Step 1: Count remainders
+	freq := RemainderHistogram(s, k)
+
 	// Step 2: Start with remainder 0 group
 	result := int32(0)
 	if freq[0] > 0 {
@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+// every challenge on empty input, d past the input and k <= 0 gives a zero answer
+// instead of a panic
+func TestEmptyAndShortInputs(t *testing.T) {
+	for _, e := range [][]int32{nil, {}, {5}, {1, 2, 3}} {
+		for _, d := range []int32{-1, 0, 3, 10} {
+			if got := activityNotifications(e, d); got != 0 {
+				t.Errorf("activityNotifications(%v, %d) = %d, want 0", e, d, got)
+			}
+			if got := activityNotifications2(e, d); got != 0 {
+				t.Errorf("activityNotifications2(%v, %d) = %d, want 0", e, d, got)
+			}
+			if got := ActivityWindowStats(e, d); len(got) != 0 {
+				t.Errorf("ActivityWindowStats(%v, %d) = %v, want none", e, d, got)
+			}
+		}
+	}
+	for _, c := range [][]int32{nil, {}, {0}} {
+		if got := jumpingOnClouds(c); got != 0 {
+			t.Errorf("jumpingOnClouds(%v) = %d, want 0", c, got)
+		}
+	}
+	for _, c := range []struct {
+		s string
+		n int64
+	}{{"", 10}, {"", 0}, {"a", 0}, {"a", -5}} {
+		if got := repeatedString(c.s, c.n); got != 0 {
+			t.Errorf("repeatedString(%q, %d) = %d, want 0", c.s, c.n, got)
+		}
+	}
+	for _, k := range []int32{-3, 0, 1, 4} {
+		if got := nonDivisibleSubset(nil, k); got != 0 {
+			t.Errorf("nonDivisibleSubset(nil, %d) = %d, want 0", k, got)
+		}
+	}
+	if got := nonDivisibleSubset([]int32{1, 2, 3}, 0); got != 0 {
+		t.Errorf("nonDivisibleSubset with k=0 = %d, want 0", got)
+	}
+	if got := superReducedString(""); got != "Empty String" {
+		t.Errorf(`superReducedString("") = %q, want "Empty String"`, got)
+	}
+	if !regularExpression("", "") || regularExpression("", "a") {
+		t.Error(`regularExpression on "" is wrong`)
+	}
+}
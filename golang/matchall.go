@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// results[i] = p.Match(inputs[i])
+func (p *Pattern) MatchAll(inputs []string) []bool {
+	results := make([]bool, len(inputs))
+	for i, s := range inputs {
+		results[i] = p.Match(s)
+	}
+	return results
+}
+
+// MatchAll that stops once ctx is done. completed is how many inputs (from the
+// front) got matched, results past that are left false and err is ctx.Err()
+func (p *Pattern) MatchAllContext(ctx context.Context, inputs []string) (results []bool, completed int, err error) {
+	results = make([]bool, len(inputs))
+	for i, s := range inputs {
+		if err := ctx.Err(); err != nil {
+			return results, i, err
+		}
+		results[i] = p.Match(s)
+	}
+	return results, len(inputs), nil
+}
+
+// MatchPrefix matches the pattern against the start of s and says how many units
+// (runes, bytes in byte mode) it used, the rest of s is left for the caller.
+// `*ab` on "aabXY" -> 3, true, leaving "XY".
+// stars are greedy and alternatives tried in order, so it's the first match the
+// backtracker finds, not necessarily the longest
+func (p *Pattern) MatchPrefix(s string) (matchedLen int, ok bool) {
+	m := p.newMatcher(p.units(s))
+	m.prefix = true
+	for _, tokens := range p.alts {
+		if m.run(tokens) {
+			return m.end, true
+		}
+	}
+	return 0, false
+}
+
+// Match with a cap on the work: maxSteps token attempts across all alternatives
+// (the backtracker's unit of work, so it bounds time without a timer).
+// exceeded means it gave up and matched is meaningless, maxSteps <= 0 always exceeds
+func (p *Pattern) MatchBudget(s string, maxSteps int) (matched bool, exceeded bool) {
+	if maxSteps <= 0 {
+		return false, true
+	}
+	m := p.newMatcher(p.units(s))
+	m.budget = maxSteps
+	for _, tokens := range p.alts {
+		if m.run(tokens) {
+			return true, false
+		}
+		if m.exceeded {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// MatchDetail is Match plus which alternative matched (alternatives are tried in
+// order, so the first that can) and how many units each star in it consumed, in
+// pattern order. a star is any unbounded repeat, `*x` or `{m,}x`.
+// `a*b|x*y` on "xyyy" -> 1, [3], true
+func (p *Pattern) MatchDetail(s string) (altIndex int, stars []int, ok bool) {
+	m := p.newMatcher(p.units(s))
+	for a, tokens := range p.alts {
+		m.took = make([]int, len(tokens))
+		if !m.run(tokens) {
+			continue
+		}
+		stars = []int{}
+		for k := range tokens {
+			if tokens[k].max == -1 {
+				stars = append(stars, m.took[k])
+			}
+		}
+		return a, stars, true
+	}
+	return -1, nil, false
+}
+
+// ErrInvalidUTF8 is what MatchValidate wraps, with the offset of the first bad byte
+var ErrInvalidUTF8 = errors.New("invalid UTF-8")
+
+// Match, but input that isn't valid UTF-8 is an error instead of being matched as
+// U+FFFD. byte mode patterns take any bytes, so they never error
+func (p *Pattern) MatchValidate(s string) (bool, error) {
+	if !p.byteMode {
+		for i := 0; i < len(s); {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				return false, fmt.Errorf("%w at byte %d", ErrInvalidUTF8, i)
+			}
+			i += size
+		}
+	}
+	return p.Match(s), nil
+}
+
+// cancels equal neighbours first (SuperReducedStringRaw) then matches what's left,
+// `b.` on "aabXcc" matches since it reduces to "bX"
+func (p *Pattern) MatchReduced(s string) bool {
+	return p.Match(SuperReducedStringRaw(s))
+}
+
+// MatchNamed is Match plus what each `(?<name>...)` group consumed, for the groups in
+// the alternative that matched. `(?<run>*a)b` on "aaab" -> {"run": "aaa"}, true.
+// stars are greedy, so where groups split a run is where the backtracker settled
+func (p *Pattern) MatchNamed(s string) (map[string]string, bool) {
+	in := p.units(s)
+	m := p.newMatcher(in)
+	for _, tokens := range p.alts {
+		m.took = make([]int, len(tokens))
+		if !m.run(tokens) {
+			continue
+		}
+		groups := map[string]string{}
+		start := map[string]int{}
+		i := 0
+		for k := range tokens {
+			name := tokens[k].group
+			if name != "" {
+				if _, ok := start[name]; !ok {
+					start[name] = i
+				}
+				groups[name] = unitString(in[start[name]:i+m.took[k]], p.byteMode)
+			}
+			i += m.took[k]
+		}
+		return groups, true
+	}
+	return nil, false
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchAllContextDeadline(t *testing.T) {
+	// each input costs the backtracker a while, no b so every split is tried
+	p := MustCompile("*a*a*a*ab")
+	inputs := make([]string, 2000)
+	for i := range inputs {
+		inputs[i] = strings.Repeat("a", 60)
+	}
+	inputs[0] = "aaaab"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	results, completed, err := p.MatchAllContext(ctx, inputs)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if completed < 1 || completed >= len(inputs) {
+		t.Fatalf("completed %d of %d, want a partial count", completed, len(inputs))
+	}
+	if !results[0] {
+		t.Error("first input finished but isn't marked as matching")
+	}
+	for i := completed; i < len(results); i++ {
+		if results[i] {
+			t.Fatalf("result %d set past completed=%d", i, completed)
+		}
+	}
+
+	results, completed, err = p.MatchAllContext(context.Background(), inputs[:3])
+	if err != nil || completed != 3 || !results[0] || results[1] || results[2] {
+		t.Errorf("no deadline: %v, %d, %v", results, completed, err)
+	}
+}
+
+func TestMatchPrefix(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		n          int
+		ok         bool
+	}{
+		{"*ab", "aabXY", 3, true}, // leaves "XY"
+		{"a*b", "abbbX", 4, true},
+		{"*ab", "XY", 0, false},
+		{"abc", "abc", 3, true},
+		{"", "xyz", 0, true},
+		{"éa", "éaé", 2, true}, // units, é is one rune
+	}
+	for _, c := range cases {
+		n, ok := MustCompile(c.pattern).MatchPrefix(c.s)
+		if n != c.n || ok != c.ok {
+			t.Errorf("%q.MatchPrefix(%q) = %d, %v, want %d, %v", c.pattern, c.s, n, ok, c.n, c.ok)
+		}
+	}
+	if n, _ := MustCompile("*ab").MatchPrefix("aabXY"); string([]rune("aabXY")[n:]) != "XY" {
+		t.Errorf("*ab on aabXY leaves %q, want XY", string([]rune("aabXY")[n:]))
+	}
+}
+
+func TestMatchBudget(t *testing.T) {
+	p := MustCompile("*a*a*a*ab")
+	s := strings.Repeat("a", 30) // no b, the backtracker tries every split
+	if _, exceeded := p.MatchBudget(s, 100); !exceeded {
+		t.Error("a 100 step budget on an exponential search wasn't exceeded")
+	}
+	if matched, exceeded := p.MatchBudget(s, 10_000_000); exceeded || matched {
+		t.Errorf("generous budget: matched %v exceeded %v, want false false", matched, exceeded)
+	}
+	if matched, exceeded := p.MatchBudget("aaaab", 10_000); exceeded || !matched {
+		t.Errorf("generous budget on a match: matched %v exceeded %v, want true false", matched, exceeded)
+	}
+	if _, exceeded := p.MatchBudget("aaaab", 0); !exceeded {
+		t.Error("a 0 step budget should always be exceeded")
+	}
+}
+
+func TestMatchDetail(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		alt        int
+		stars      []int
+		ok         bool
+	}{
+		{"a*b|x*y", "xyyy", 1, []int{3}, true}, // second alternative
+		{"a*b|x*y", "abb", 0, []int{2}, true},
+		{"*a*ab", "aaaab", 0, []int{3, 1}, true}, // greedy, the first star leaves the second one unit
+		{"{2,}.c", "xyzc", 0, []int{3}, true},
+		{"abc", "abc", 0, []int{}, true},
+		{"a*b", "ac", -1, nil, false},
+	}
+	for _, c := range cases {
+		alt, stars, ok := MustCompile(c.pattern).MatchDetail(c.s)
+		if alt != c.alt || !slices.Equal(stars, c.stars) || ok != c.ok {
+			t.Errorf("%q.MatchDetail(%q) = %d, %v, %v, want %d, %v, %v", c.pattern, c.s, alt, stars, ok, c.alt, c.stars, c.ok)
+		}
+	}
+}
+
+func TestMatchValidate(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		byteMode   bool
+		want       bool
+		errAt      string // "" for no error
+	}{
+		{"a.c", "aéc", false, true, ""},
+		{"a.c", "a\xffc", false, false, "at byte 1"},
+		{"*.", "é\xe9", false, false, "at byte 2"}, // a lead byte with nothing after it
+		{"a.c", "a\xffc", true, true, ""},          // byte mode takes any bytes
+	}
+	for _, c := range cases {
+		var opts []Option
+		if c.byteMode {
+			opts = append(opts, WithByteMode())
+		}
+		got, err := MustCompile(c.pattern, opts...).MatchValidate(c.s)
+		switch {
+		case c.errAt == "" && err != nil:
+			t.Errorf("%q.MatchValidate(%q): %v", c.pattern, c.s, err)
+		case c.errAt != "" && (!errors.Is(err, ErrInvalidUTF8) || !strings.HasSuffix(err.Error(), c.errAt)):
+			t.Errorf("%q.MatchValidate(%q) error = %v, want ErrInvalidUTF8 %s", c.pattern, c.s, err, c.errAt)
+		case got != c.want:
+			t.Errorf("%q.MatchValidate(%q) = %v, want %v", c.pattern, c.s, got, c.want)
+		}
+	}
+}
+
+func TestMatchReduced(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"b.", "aabXcc", true}, // reduces to "bX"
+		{"b.", "aabXc", false}, // "bXc"
+		{"*a", "aaa", true},    // "a"
+		{"*a", "aaaa", false},  // nothing left
+		{"x?", "abba", true},   // "" too
+	}
+	for _, c := range cases {
+		if got := MustCompile(c.pattern).MatchReduced(c.s); got != c.want {
+			t.Errorf("%q.MatchReduced(%q) = %v, want %v", c.pattern, c.s, got, c.want)
+		}
+	}
+}
+
+func TestMatchNamed(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		groups     map[string]string
+		ok         bool
+	}{
+		{"(?<run>*a)b", "aaab", map[string]string{"run": "aaa"}, true},
+		{"(?<key>*[a-z])=(?<val>*[0-9])", "port=8080", map[string]string{"key": "port", "val": "8080"}, true},
+		{"(?<x>*a)(?<y>*a)", "aaaa", map[string]string{"x": "aaa", "y": "a"}, true}, // greedy split
+		{"(?<opt>b?)c", "c", map[string]string{"opt": ""}, true},
+		{"x|(?<n>é.)", "éz", map[string]string{"n": "éz"}, true}, // only the alternative that matched
+		{"(?<n>a)b", "ac", nil, false},
+		{"abc", "abc", map[string]string{}, true},
+	}
+	for _, c := range cases {
+		groups, ok := MustCompile(c.pattern).MatchNamed(c.s)
+		if ok != c.ok || !maps.Equal(groups, c.groups) || (c.groups == nil) != (groups == nil) {
+			t.Errorf("%q.MatchNamed(%q) = %v, %v, want %v, %v", c.pattern, c.s, groups, ok, c.groups, c.ok)
+		}
+	}
+}
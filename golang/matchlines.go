@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+)
+
+// LineMatch is one input line and whether it matched, Line counts from 1.
+// if reading r fails the last value has only Err set
+type LineMatch struct {
+	Line    int
+	Text    string
+	Matched bool
+	Err     error
+}
+
+// MatchLines greps r line by line, at most `concurrency` lines being matched at once,
+// and sends the results in line order. the channel is closed at the end of r or once
+// ctx is done.
+// every line gets its own 1 slot result channel, queued in line order on `pending`,
+// so the sender just waits on them one by one however the workers finish (the reorder buffer)
+func MatchLines(ctx context.Context, r io.Reader, p *Pattern, concurrency int) (<-chan LineMatch, error) {
+	if p == nil {
+		return nil, errors.New("MatchLines: nil pattern")
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	out := make(chan LineMatch)
+	pending := make(chan chan LineMatch, concurrency)
+	throttle := NewThrottle(concurrency)
+
+	// reader, hands each line to a worker
+	go func() {
+		defer close(pending)
+		scanner := bufio.NewScanner(r)
+		for line := 1; scanner.Scan(); line++ {
+			text := scanner.Text()
+			if throttle.Acquire(ctx) != nil {
+				return
+			}
+			res := make(chan LineMatch, 1) // buffered so a worker never blocks
+			select {
+			case pending <- res:
+			case <-ctx.Done():
+				throttle.Release()
+				return
+			}
+			go func() {
+				defer throttle.Release()
+				res <- LineMatch{Line: line, Text: text, Matched: p.Match(text)}
+			}()
+		}
+		if err := scanner.Err(); err != nil {
+			res := make(chan LineMatch, 1)
+			res <- LineMatch{Err: err}
+			select {
+			case pending <- res:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	// sender, waits on results in line order
+	go func() {
+		defer close(out)
+		for res := range pending {
+			select {
+			case out <- <-res:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
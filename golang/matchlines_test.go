@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestMatchLinesOrdered(t *testing.T) {
+	p := MustCompile("*a*b")
+	var sb strings.Builder
+	want := []LineMatch{}
+	for i := 1; i <= 200; i++ {
+		text := strings.Repeat("a", i%7) + strings.Repeat("b", i%3)
+		fmt.Fprintln(&sb, text)
+		want = append(want, LineMatch{Line: i, Text: text, Matched: p.Match(text)})
+	}
+	for _, concurrency := range []int{1, 4, 50} {
+		out, err := MatchLines(context.Background(), strings.NewReader(sb.String()), p, concurrency)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []LineMatch{}
+		for res := range out {
+			got = append(got, res)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("concurrency %d: %d results, want %d", concurrency, len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("concurrency %d: result %d = %+v, want %+v", concurrency, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestMatchLinesReadError(t *testing.T) {
+	readErr := errors.New("disk on fire")
+	r := io.MultiReader(strings.NewReader("ab\nb\n"), iotest.ErrReader(readErr))
+	out, err := MatchLines(context.Background(), r, MustCompile("*ab"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []LineMatch{}
+	for res := range out {
+		got = append(got, res)
+	}
+	if len(got) != 3 || !got[0].Matched || got[1].Matched || !errors.Is(got[2].Err, readErr) {
+		t.Errorf("got %+v, want ab matched, b not, then the read error", got)
+	}
+}
+
+func TestMatchLinesCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out, err := MatchLines(ctx, strings.NewReader(strings.Repeat("a\n", 10000)), MustCompile("a"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-out
+	cancel()
+	n := 0
+	for range out { // has to close without reading the rest
+		n++
+	}
+	if n >= 9999 {
+		t.Errorf("read %d more lines after cancelling", n)
+	}
+
+	if _, err := MatchLines(context.Background(), strings.NewReader(""), nil, 1); err == nil {
+		t.Error("MatchLines with a nil pattern returned no error")
+	}
+}
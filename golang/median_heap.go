@@ -0,0 +1,112 @@
+package main
+
+import "container/heap"
+
+// medians of every full window, result[i] is the median of values[i : i+window].
+// the counting sort in WindowCounter needs values in 0..200, this works for any int32:
+// lo is a max-heap of the lower half, hi a min-heap of the upper half, values leaving
+// the window are only marked and popped once they reach a top (lazy deletion),
+// so each step is O(log window)
+func SlidingMedianHeap(values []int32, window int) []float64 {
+	if window <= 0 || len(values) < window {
+		return []float64{}
+	}
+	m := &slidingMedian{
+		lo:      halfHeap{sign: -1}, // stores -v so the biggest of the lower half is on top
+		hi:      halfHeap{sign: 1},
+		delayed: map[int64]int{},
+	}
+	medians := make([]float64, 0, len(values)-window+1)
+	for i, v := range values {
+		m.insert(int64(v))
+		if i >= window {
+			m.erase(int64(values[i-window]))
+		}
+		if i >= window-1 {
+			medians = append(medians, m.median(window))
+		}
+	}
+	return medians
+}
+
+type slidingMedian struct {
+	lo, hi         halfHeap
+	loSize, hiSize int           // live elements, not counting ones waiting to be deleted
+	delayed        map[int64]int // value -> how many copies left the window but are still in a heap
+}
+
+func (m *slidingMedian) insert(v int64) {
+	if m.lo.Len() == 0 || v <= m.lo.top() {
+		heap.Push(&m.lo, v)
+		m.loSize++
+	} else {
+		heap.Push(&m.hi, v)
+		m.hiSize++
+	}
+	m.balance()
+}
+
+func (m *slidingMedian) erase(v int64) {
+	m.delayed[v]++
+	if v <= m.lo.top() {
+		m.loSize--
+		if v == m.lo.top() {
+			m.prune(&m.lo)
+		}
+	} else {
+		m.hiSize--
+		if v == m.hi.top() {
+			m.prune(&m.hi)
+		}
+	}
+	m.balance()
+}
+
+// keeps loSize == hiSize or loSize == hiSize+1
+func (m *slidingMedian) balance() {
+	if m.loSize > m.hiSize+1 {
+		heap.Push(&m.hi, m.lo.pop())
+		m.loSize--
+		m.hiSize++
+		m.prune(&m.lo)
+	} else if m.loSize < m.hiSize {
+		heap.Push(&m.lo, m.hi.pop())
+		m.hiSize--
+		m.loSize++
+		m.prune(&m.hi)
+	}
+}
+
+// pops tops that already left the window
+func (m *slidingMedian) prune(h *halfHeap) {
+	for h.Len() > 0 && m.delayed[h.top()] > 0 {
+		m.delayed[h.top()]--
+		h.pop()
+	}
+}
+
+func (m *slidingMedian) median(window int) float64 {
+	if window%2 == 1 {
+		return float64(m.lo.top())
+	}
+	return (float64(m.lo.top()) + float64(m.hi.top())) / 2.0
+}
+
+// min-heap of sign*v, sign -1 turns it into a max-heap of v
+type halfHeap struct {
+	data []int64
+	sign int64
+}
+
+func (h halfHeap) Len() int           { return len(h.data) }
+func (h halfHeap) Less(i, j int) bool { return h.data[i] < h.data[j] }
+func (h halfHeap) Swap(i, j int)      { h.data[i], h.data[j] = h.data[j], h.data[i] }
+func (h *halfHeap) Push(x any)        { h.data = append(h.data, h.sign*x.(int64)) }
+func (h *halfHeap) Pop() any {
+	last := h.data[len(h.data)-1]
+	h.data = h.data[:len(h.data)-1]
+	return h.sign * last
+}
+
+func (h *halfHeap) top() int64 { return h.sign * h.data[0] }
+func (h *halfHeap) pop() int64 { return heap.Pop(h).(int64) }
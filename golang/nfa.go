@@ -0,0 +1,148 @@
+package main
+
+// Thompson NFA for a Pattern, simulated RE2 style by stepping a set of states
+// over the input once, so no pattern can blow up exponentially.
+// O(len(input) * len(prog)) worst case
+
+type instOp int
+
+const (
+	instChar  instOp = iota // consume one rune matching tok
+	instSplit               // fork to x and y
+	instJmp                 // go to x
+	instMatch               // accept
+)
+
+type inst struct {
+	op  instOp
+	tok *token // for instChar
+	x   int
+	y   int
+}
+
+type nfaProg struct {
+	insts []inst
+	start int
+}
+
+func compileNFA(alts [][]token) *nfaProg {
+	prog := &nfaProg{}
+	emit := func(in inst) int {
+		prog.insts = append(prog.insts, in)
+		return len(prog.insts) - 1
+	}
+	// alt 0 | alt 1 | ... compiles to a chain of splits, each alt jumps to the shared match
+	exits := []int{}
+	for a, tokens := range alts {
+		split := -1
+		if a < len(alts)-1 {
+			split = emit(inst{op: instSplit, x: len(prog.insts) + 1})
+		}
+		compileTokens(prog, emit, tokens)
+		if a < len(alts)-1 {
+			exits = append(exits, emit(inst{op: instJmp}))
+			prog.insts[split].y = len(prog.insts)
+		}
+	}
+	end := emit(inst{op: instMatch})
+	for _, pc := range exits {
+		prog.insts[pc].x = end
+	}
+	return prog
+}
+
+func compileTokens(prog *nfaProg, emit func(inst) int, tokens []token) {
+	for i := range tokens {
+		t := &tokens[i]
+		// mandatory copies
+		last := -1
+		for k := 0; k < t.min; k++ {
+			last = emit(inst{op: instChar, tok: t})
+		}
+		switch {
+		case t.max == -1 && last != -1:
+			// x+ : loop back to the last mandatory copy
+			emit(inst{op: instSplit, x: last, y: len(prog.insts) + 1})
+		case t.max == -1:
+			// x* : split -> char -> jmp split
+			split := emit(inst{op: instSplit, x: len(prog.insts) + 1})
+			emit(inst{op: instChar, tok: t})
+			emit(inst{op: instJmp, x: split})
+			prog.insts[split].y = len(prog.insts)
+		default:
+			// optional copies up to max, each one may jump straight to the end
+			splits := []int{}
+			for k := t.min; k < t.max; k++ {
+				splits = append(splits, emit(inst{op: instSplit, x: len(prog.insts) + 1}))
+				emit(inst{op: instChar, tok: t})
+			}
+			for _, sp := range splits {
+				prog.insts[sp].y = len(prog.insts)
+			}
+		}
+	}
+}
+
+// MatchNFA gives the same answer as Match but never backtracks.
+// possessive repeats can't be expressed as NFA states, those patterns go to Match
+func (p *Pattern) MatchNFA(s string) bool {
+	if p.hasPossessive() {
+		return p.Match(s)
+	}
+	p.nfaOnce.Do(func() { p.nfa = compileNFA(p.alts) })
+	return p.nfa.match(p.units(s))
+}
+
+func (prog *nfaProg) match(in []rune) bool {
+	seen := make([]int, len(prog.insts)) // generation an inst was last added in
+	gen := 0
+	var add func(list []int, pc int) []int
+	add = func(list []int, pc int) []int {
+		if seen[pc] == gen {
+			return list
+		}
+		seen[pc] = gen
+		switch prog.insts[pc].op {
+		case instJmp:
+			return add(list, prog.insts[pc].x)
+		case instSplit:
+			list = add(list, prog.insts[pc].x)
+			return add(list, prog.insts[pc].y)
+		}
+		return append(list, pc)
+	}
+
+	gen++
+	clist := add(nil, prog.start)
+	nlist := []int{}
+	for _, r := range in {
+		gen++
+		nlist = nlist[:0]
+		for _, pc := range clist {
+			if prog.insts[pc].op == instChar && prog.insts[pc].tok.matches(r) {
+				nlist = add(nlist, pc+1)
+			}
+		}
+		clist, nlist = nlist, clist
+		if len(clist) == 0 {
+			return false
+		}
+	}
+	for _, pc := range clist {
+		if prog.insts[pc].op == instMatch {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Pattern) hasPossessive() bool {
+	for _, tokens := range p.alts {
+		for i := range tokens {
+			if tokens[i].possessive {
+				return true
+			}
+		}
+	}
+	return false
+}
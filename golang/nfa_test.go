@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestMatchNFAAgreesWithMatch(t *testing.T) {
+	for _, c := range matchCases {
+		if got := MustCompile(c.pattern).MatchNFA(c.s); got != c.want {
+			t.Errorf("%q.MatchNFA(%q) = %v, want %v", c.pattern, c.s, got, c.want)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 300; trial++ {
+		p := MustCompile(randomPattern(rng, false))
+		for k := 0; k < 20; k++ {
+			s := randomInput(rng, "abc", 8)
+			if got, want := p.MatchNFA(s), p.Match(s); got != want {
+				t.Fatalf("%q.MatchNFA(%q) = %v, Match says %v", p, s, got, want)
+			}
+		}
+	}
+}
+
+// time per op should grow linearly with n, the backtracker tries O(n^5) splits here
+func BenchmarkMatchNFAStars(b *testing.B) {
+	p := MustCompile("*a*a*a*a*ab")
+	for _, n := range []int{100, 1000, 10000} {
+		s := strings.Repeat("a", n) // no b, so every way of splitting the a's fails
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				p.MatchNFA(s)
+			}
+		})
+	}
+}
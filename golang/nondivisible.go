@@ -0,0 +1,249 @@
+package main
+
+import "errors"
+
+// how many elements have to go so no two of the rest sum to a multiple of k
+func MinRemovalsForNonDivisible(s []int32, k int32) int {
+	return len(s) - int(nonDivisibleSubset(s, k))
+}
+
+// same choice as nonDivisibleSubset but returns the elements themselves, in input order.
+// len(result) == nonDivisibleSubset(s, k)
+func NonDivisibleSubsetElements(s []int32, k int32) []int32 {
+	if k <= 0 {
+		return []int32{}
+	}
+	freq := RemainderHistogram(s, k)
+
+	// keep[r] = how many elements with remainder r go in
+	keep := make([]int32, k)
+	keep[0] = min(freq[0], 1)
+	for r := int32(1); r <= k/2; r++ {
+		switch {
+		case r == k-r:
+			keep[r] = min(freq[r], 1) // middle group when k is even
+		case freq[r] > freq[k-r]:
+			keep[r] = freq[r]
+		default:
+			keep[k-r] = freq[k-r]
+		}
+	}
+
+	res := []int32{}
+	for _, num := range s {
+		if r := remainderOf(num, k); keep[r] > 0 {
+			keep[r]--
+			res = append(res, num)
+		}
+	}
+	return res
+}
+
+// is there any pair in s summing to a multiple of k, same remainder buckets as
+// nonDivisibleSubset: a pair exists when a bucket meets its complement (r, k-r),
+// or bucket 0 / the middle bucket holds two
+func HasPairDivisibleBy(s []int32, k int32) bool {
+	if k <= 0 {
+		return false
+	}
+	freq := RemainderHistogram(s, k)
+	if freq[0] >= 2 {
+		return true
+	}
+	for r := int32(1); r <= k/2; r++ {
+		if r == k-r && freq[r] >= 2 || r != k-r && freq[r] > 0 && freq[k-r] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// freq[r] = how many elements of s leave remainder r mod k, the array nonDivisibleSubset
+// builds in Step 1. sums to len(s), empty for k <= 0
+func RemainderHistogram(s []int32, k int32) []int32 {
+	if k <= 0 {
+		return []int32{}
+	}
+	freq := make([]int32, k)
+	for _, num := range s {
+		freq[remainderOf(num, k)]++
+	}
+	return freq
+}
+
+// num mod k in 0..k-1, Go's % keeps the sign so -1 % 4 is -1 not 3
+func remainderOf(num, k int32) int32 {
+	return (num%k + k) % k
+}
+
+// nonDivisibleSubset kept up to date as elements come in, Size() after each Add
+// equals nonDivisibleSubset on everything added so far. an Add only touches the
+// pair (r, k-r) its remainder falls in, so it's O(1) instead of O(n + k)
+type NonDivisibleSet struct {
+	k    int32
+	freq []int32
+	size int32
+}
+
+func NewNonDivisibleSet(k int32) *NonDivisibleSet {
+	if k <= 0 {
+		return &NonDivisibleSet{k: k} // Size stays 0, same as nonDivisibleSubset
+	}
+	return &NonDivisibleSet{k: k, freq: make([]int32, k)}
+}
+
+func (n *NonDivisibleSet) Add(x int32) {
+	if n.k <= 0 {
+		return
+	}
+	r := remainderOf(x, n.k)
+	n.size -= n.pairSize(r)
+	n.freq[r]++
+	n.size += n.pairSize(r)
+}
+
+// x has to have been added
+func (n *NonDivisibleSet) Remove(x int32) {
+	if n.k <= 0 {
+		return
+	}
+	r := remainderOf(x, n.k)
+	n.size -= n.pairSize(r)
+	n.freq[r]--
+	n.size += n.pairSize(r)
+}
+
+func (n *NonDivisibleSet) Size() int32 {
+	return n.size
+}
+
+// what the group of r and its complement k-r adds to the subset
+func (n *NonDivisibleSet) pairSize(r int32) int32 {
+	c := (n.k - r) % n.k
+	if r == c {
+		return min(n.freq[r], 1) // remainder 0, or the middle one when k is even
+	}
+	return max(n.freq[r], n.freq[c])
+}
+
+// res[i] = nonDivisibleSubset(s[i:i+window], k), slid with a NonDivisibleSet so each
+// step is one Remove and one Add instead of a fresh count. empty when window > len(s)
+func NonDivisibleSubsetWindow(s []int32, k int32, window int) ([]int32, error) {
+	if window <= 0 {
+		return nil, errors.New("NonDivisibleSubsetWindow: window must be positive")
+	}
+	res := []int32{}
+	if window > len(s) {
+		return res, nil
+	}
+	set := NewNonDivisibleSet(k)
+	for i, x := range s {
+		set.Add(x)
+		if i >= window {
+			set.Remove(s[i-window])
+		}
+		if i >= window-1 {
+			res = append(res, set.Size())
+		}
+	}
+	return res, nil
+}
+
+// remainders nonDivisibleSubset leaves elements out of, ascending: the smaller group
+// of each (r, k-r) pair goes entirely (r on a tie, same choice as
+// NonDivisibleSubsetElements), and 0 / k/2 when they hold more than the one that can stay.
+// empty groups aren't listed, nothing was dropped from them
+func NonDivisibleSubsetDropped(s []int32, k int32) []int32 {
+	dropped := []int32{}
+	if k <= 0 {
+		return dropped
+	}
+	freq := RemainderHistogram(s, k)
+	if freq[0] > 1 {
+		dropped = append(dropped, 0)
+	}
+	for r := int32(1); r < k; r++ {
+		c := k - r
+		switch {
+		case freq[r] == 0:
+		case r == c:
+			if freq[r] > 1 {
+				dropped = append(dropped, r)
+			}
+		case r < c && freq[r] <= freq[c], r > c && freq[r] < freq[c]:
+			dropped = append(dropped, r)
+		}
+	}
+	return dropped
+}
+
+// nonDivisibleSubset maximising total weight instead of size, weights[i] is s[i]'s.
+// same pairing: each (r, k-r) pair keeps whichever group weighs more, 0 and k/2 keep
+// their heaviest single element. a negative weight is never worth taking, so groups only
+// sum their positive ones. 0 when the lengths differ or k <= 0
+func MaxWeightNonDivisibleSubset(s []int32, weights []int64, k int32) int64 {
+	if k <= 0 || len(s) != len(weights) {
+		return 0
+	}
+	group := make([]int64, k) // positive weight in each remainder group
+	best := make([]int64, k)  // heaviest single element in each group, 0 if none is positive
+	for i, num := range s {
+		r := remainderOf(num, k)
+		if w := weights[i]; w > 0 {
+			group[r] += w
+			best[r] = max(best[r], w)
+		}
+	}
+
+	total := best[0]
+	for r := int32(1); r <= k/2; r++ {
+		if r == k-r {
+			total += best[r]
+		} else {
+			total += max(group[r], group[k-r])
+		}
+	}
+	return total
+}
+
+// one step of nonDivisibleSubset: the group of remainder R against its complement.
+// R == Complement for remainder 0 and for k/2 when k is even, a group paired with itself
+type RemainderChoice struct {
+	R, Complement   int32
+	SizeR, SizeComp int32  // elements with remainder R / Complement
+	Chosen          int32  // remainder whose elements go in, -1 when both groups are empty
+	Taken           int32  // how many of them, what the step adds to the subset
+	Reason          string // why Chosen won
+}
+
+// the decisions nonDivisibleSubset makes, in the order it makes them: remainder 0 first,
+// then every pair r, k-r for r = 1..k/2. the Taken fields sum to nonDivisibleSubset(s, k).
+// a tie goes to k-r like it does there. empty for k <= 0
+func NonDivisibleSubsetExplain(s []int32, k int32) []RemainderChoice {
+	if k <= 0 {
+		return []RemainderChoice{}
+	}
+	freq := RemainderHistogram(s, k)
+	steps := make([]RemainderChoice, 0, k/2+1)
+	for r := int32(0); r <= k/2; r++ {
+		c := RemainderChoice{R: r, Complement: (k - r) % k, SizeR: freq[r], SizeComp: freq[(k-r)%k], Chosen: -1}
+		switch {
+		case c.SizeR == 0 && c.SizeComp == 0:
+			c.Reason = "no elements with either remainder"
+		case r == c.Complement:
+			c.Chosen, c.Taken = r, 1
+			c.Reason = "any two of this group sum to a multiple of k, only one can stay"
+		case c.SizeR > c.SizeComp:
+			c.Chosen, c.Taken = r, c.SizeR
+			c.Reason = "larger group, the two can't be mixed"
+		case c.SizeR < c.SizeComp:
+			c.Chosen, c.Taken = c.Complement, c.SizeComp
+			c.Reason = "larger group, the two can't be mixed"
+		default:
+			c.Chosen, c.Taken = c.Complement, c.SizeComp
+			c.Reason = "tie, either group gives the same size"
+		}
+		steps = append(steps, c)
+	}
+	return steps
+}
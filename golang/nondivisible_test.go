@@ -0,0 +1,310 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// seeded small input for the brute force oracles, values may be negative or 0
+func randomSmallSet(rng *rand.Rand, n int) []int32 {
+	s := make([]int32, n)
+	for i := range s {
+		s[i] = int32(rng.Intn(50) - 10)
+	}
+	return s
+}
+
+// reference for nonDivisibleSubset, tries every subset so only for small s (2^len(s)).
+// the sum is int64, two int32s near the limit would overflow
+func nonDivisibleSubsetBrute(s []int32, k int32) int32 {
+	best := int32(0)
+	for mask := 0; mask < 1<<len(s); mask++ {
+		size := int32(0)
+		ok := true
+		for i := 0; i < len(s) && ok; i++ {
+			if mask&(1<<i) == 0 {
+				continue
+			}
+			size++
+			for j := i + 1; j < len(s); j++ {
+				if mask&(1<<j) != 0 && (int64(s[i])+int64(s[j]))%int64(k) == 0 {
+					ok = false
+					break
+				}
+			}
+		}
+		if ok && size > best {
+			best = size
+		}
+	}
+	return best
+}
+
+func TestMinRemovalsForNonDivisible(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 500; trial++ {
+		s := randomSmallSet(rng, rng.Intn(11))
+		k := int32(1 + rng.Intn(7))
+		want := len(s) - int(nonDivisibleSubsetBrute(s, k))
+		if got := MinRemovalsForNonDivisible(s, k); got != want {
+			t.Fatalf("MinRemovalsForNonDivisible(%v, %d) = %d, brute force says %d", s, k, got, want)
+		}
+	}
+
+	// MaxInt32 + 2 is a multiple of 3, summed in int32 it wraps to one that isn't
+	big := []int32{math.MaxInt32, 2}
+	if got := nonDivisibleSubsetBrute(big, 3); got != 1 {
+		t.Errorf("nonDivisibleSubsetBrute(%v, 3) = %d, want 1", big, got)
+	}
+	if got := MinRemovalsForNonDivisible(big, 3); got != 1 {
+		t.Errorf("MinRemovalsForNonDivisible(%v, 3) = %d, want 1", big, got)
+	}
+}
+
+// 10^6 values with a moderate k, nonDivisibleSubset is O(n + k) so this should stay
+// in the low ms. an O(n*k) slip shows up straight away
+func BenchmarkNonDivisibleSubset(b *testing.B) {
+	rng := rand.New(rand.NewSource(42))
+	s := make([]int32, 1_000_000)
+	for i := range s {
+		s[i] = rng.Int31n(1_000_000_000) + 1
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		nonDivisibleSubset(s, 100)
+	}
+}
+
+func TestNonDivisibleSubsetElementsShuffled(t *testing.T) {
+	base := []int32{19, 10, 12, 10, 24, 25, 22, 1, 2, 3, 4, 5, 6, 7, 8, 9, 11, 13, 14, 16, 18, 20}
+	rng := rand.New(rand.NewSource(7))
+	for _, k := range []int32{1, 2, 3, 4, 5, 7, 10} {
+		want := nonDivisibleSubset(base, k)
+		s := append([]int32{}, base...)
+		for shuffle := 0; shuffle < 20; shuffle++ {
+			rng.Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+			sub := NonDivisibleSubsetElements(s, k)
+			if int32(len(sub)) != want {
+				t.Fatalf("k=%d %v: %d elements, nonDivisibleSubset says %d", k, s, len(sub), want)
+			}
+			if CountPairs(sub, func(a, b int32) bool { return (a+b)%k == 0 }) > 0 {
+				t.Fatalf("k=%d %v: subset %v has a pair summing to a multiple of k", k, s, sub)
+			}
+			left := map[int32]int{} // sub has to be drawn from s
+			for _, v := range s {
+				left[v]++
+			}
+			for _, v := range sub {
+				if left[v]--; left[v] < 0 {
+					t.Fatalf("k=%d: subset %v uses %d more often than %v has it", k, sub, v, s)
+				}
+			}
+		}
+	}
+}
+
+func TestRemainderHistogram(t *testing.T) {
+	got := RemainderHistogram([]int32{19, 10, 12, 10, 24, 25, 22, -1, -4, -6}, 4)
+	want := []int32{3, 1, 4, 2} // -4 -> 0, -1 -> 3, -6 -> 2
+	if !slices.Equal(got, want) {
+		t.Errorf("RemainderHistogram = %v, want %v", got, want)
+	}
+	if got := RemainderHistogram([]int32{1, 2}, 0); len(got) != 0 {
+		t.Errorf("RemainderHistogram with k=0 = %v, want empty", got)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		s := randomSmallSet(rng, rng.Intn(20))
+		k := int32(1 + rng.Intn(9))
+		hist := RemainderHistogram(s, k)
+		manual := make([]int32, k)
+		total := int32(0)
+		for _, v := range s {
+			r := v % k
+			if r < 0 {
+				r += k
+			}
+			manual[r]++
+		}
+		for _, n := range hist {
+			total += n
+		}
+		if total != int32(len(s)) || !slices.Equal(hist, manual) {
+			t.Fatalf("RemainderHistogram(%v, %d) = %v, want %v", s, k, hist, manual)
+		}
+	}
+}
+
+func TestNonDivisibleSetAdd(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, k := range []int32{1, 2, 3, 4, 7, 10} {
+		set := NewNonDivisibleSet(k)
+		added := []int32{}
+		for i := 0; i < 60; i++ {
+			x := int32(rng.Intn(100) - 20)
+			set.Add(x)
+			added = append(added, x)
+			if got, want := set.Size(), nonDivisibleSubset(added, k); got != want {
+				t.Fatalf("k=%d after adding %v: Size() = %d, nonDivisibleSubset says %d", k, added, got, want)
+			}
+		}
+		for len(added) > 0 { // and back down again
+			j := rng.Intn(len(added))
+			set.Remove(added[j])
+			added = append(added[:j], added[j+1:]...)
+			if got, want := set.Size(), nonDivisibleSubset(added, k); got != want {
+				t.Fatalf("k=%d after a Remove, left %v: Size() = %d, want %d", k, added, got, want)
+			}
+		}
+	}
+	zero := NewNonDivisibleSet(0)
+	zero.Add(3)
+	if zero.Size() != 0 {
+		t.Errorf("k=0: Size() = %d after an Add, want 0", zero.Size())
+	}
+}
+
+func TestNonDivisibleSubsetWindow(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for trial := 0; trial < 200; trial++ {
+		s := randomSmallSet(rng, rng.Intn(30))
+		k := int32(1 + rng.Intn(8))
+		window := 1 + rng.Intn(10)
+		got, err := NonDivisibleSubsetWindow(s, k, window)
+		if err != nil {
+			t.Fatalf("NonDivisibleSubsetWindow(%v, %d, %d): %v", s, k, window, err)
+		}
+		want := []int32{}
+		for i := 0; i+window <= len(s); i++ {
+			want = append(want, nonDivisibleSubset(s[i:i+window], k))
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("NonDivisibleSubsetWindow(%v, %d, %d) = %v, want %v", s, k, window, got, want)
+		}
+	}
+	if _, err := NonDivisibleSubsetWindow([]int32{1, 2}, 3, 0); err == nil {
+		t.Error("window 0: no error")
+	}
+}
+
+func TestNonDivisibleSubsetDropped(t *testing.T) {
+	// remainders mod 4: 1 1 2 2 3 0 0 -> keep both 1s, one 2 and one 0
+	if got, want := NonDivisibleSubsetDropped([]int32{1, 5, 2, 6, 3, 4, 8}, 4), []int32{0, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("NonDivisibleSubsetDropped(...) = %v, want %v", got, want)
+	}
+	// 2 and 5 mod 7 tie, the smaller remainder goes
+	if got, want := NonDivisibleSubsetDropped([]int32{2, 5}, 7), []int32{2}; !slices.Equal(got, want) {
+		t.Errorf("tie: %v, want %v", got, want)
+	}
+
+	rng := rand.New(rand.NewSource(4))
+	for trial := 0; trial < 500; trial++ {
+		s := randomSmallSet(rng, rng.Intn(20))
+		k := int32(1 + rng.Intn(9))
+		dropped := NonDivisibleSubsetDropped(s, k)
+		freq := RemainderHistogram(s, k)
+		if !slices.IsSorted(dropped) {
+			t.Fatalf("NonDivisibleSubsetDropped(%v, %d) = %v, not ascending", s, k, dropped)
+		}
+		kept := int32(0)
+		for r := int32(0); r < k; r++ {
+			c := (k - r) % k
+			gone := slices.Contains(dropped, r)
+			switch {
+			case gone && freq[r] == 0:
+				t.Fatalf("%v, k=%d: dropped %v lists empty remainder %d", s, k, dropped, r)
+			case r == c:
+				kept += min(freq[r], 1)
+			case gone && slices.Contains(dropped, c):
+				t.Fatalf("%v, k=%d: dropped %v lists both %d and %d", s, k, dropped, r, c)
+			case !gone:
+				kept += freq[r]
+			}
+		}
+		if want := nonDivisibleSubset(s, k); kept != want {
+			t.Fatalf("%v, k=%d: keeping all but %v leaves %d, nonDivisibleSubset says %d", s, k, dropped, kept, want)
+		}
+	}
+}
+
+func TestMaxWeightNonDivisibleSubset(t *testing.T) {
+	// remainders mod 3: 1 1 2, by count the two 1s win, by weight the 2 does
+	s := []int32{1, 4, 2}
+	if got := MaxWeightNonDivisibleSubset(s, []int64{1, 1, 1}, 3); got != 2 {
+		t.Errorf("unit weights = %d, want 2", got)
+	}
+	if got := MaxWeightNonDivisibleSubset(s, []int64{1, 1, 5}, 3); got != 5 {
+		t.Errorf("heavy 2 = %d, want 5", got)
+	}
+	if got := MaxWeightNonDivisibleSubset(s, []int64{1}, 3); got != 0 {
+		t.Errorf("mismatched lengths = %d, want 0", got)
+	}
+
+	rng := rand.New(rand.NewSource(5))
+	for trial := 0; trial < 300; trial++ {
+		s := randomSmallSet(rng, rng.Intn(10))
+		k := int32(1 + rng.Intn(6))
+		weights := make([]int64, len(s))
+		for i := range weights {
+			weights[i] = int64(rng.Intn(20) - 5)
+		}
+		want := int64(0) // every subset with no pair summing to a multiple of k
+		for mask := 0; mask < 1<<len(s); mask++ {
+			ok, total := true, int64(0)
+			for i := range s {
+				if mask&(1<<i) == 0 {
+					continue
+				}
+				total += weights[i]
+				for j := i + 1; j < len(s); j++ {
+					if mask&(1<<j) != 0 && (int64(s[i])+int64(s[j]))%int64(k) == 0 {
+						ok = false
+					}
+				}
+			}
+			if ok {
+				want = max(want, total)
+			}
+		}
+		if got := MaxWeightNonDivisibleSubset(s, weights, k); got != want {
+			t.Fatalf("MaxWeightNonDivisibleSubset(%v, %v, %d) = %d, brute force says %d", s, weights, k, got, want)
+		}
+	}
+}
+
+func TestNonDivisibleSubsetExplain(t *testing.T) {
+	got := NonDivisibleSubsetExplain([]int32{1, 7, 2, 4}, 3) // the HackerRank sample, 3
+	want := []RemainderChoice{
+		{R: 0, Complement: 0, Chosen: -1, Reason: "no elements with either remainder"},
+		{R: 1, Complement: 2, SizeR: 3, SizeComp: 1, Chosen: 1, Taken: 3, Reason: "larger group, the two can't be mixed"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("NonDivisibleSubsetExplain(sample) = %+v, want %+v", got, want)
+	}
+
+	rng := rand.New(rand.NewSource(6))
+	for trial := 0; trial < 500; trial++ {
+		s := randomSmallSet(rng, rng.Intn(25))
+		k := int32(1 + rng.Intn(10))
+		steps := NonDivisibleSubsetExplain(s, k)
+		if len(steps) != int(k/2+1) {
+			t.Fatalf("k=%d: %d steps, want %d", k, len(steps), k/2+1)
+		}
+		taken := int32(0)
+		for i, st := range steps {
+			if st.R != int32(i) || (st.R+st.Complement)%k != 0 {
+				t.Fatalf("%v, k=%d: step %d is %+v", s, k, i, st)
+			}
+			if st.Chosen != -1 && st.Chosen != st.R && st.Chosen != st.Complement {
+				t.Fatalf("%v, k=%d: step %+v chose a remainder it wasn't comparing", s, k, st)
+			}
+			taken += st.Taken
+		}
+		if want := nonDivisibleSubset(s, k); taken != want {
+			t.Fatalf("%v, k=%d: Taken sums to %d, nonDivisibleSubset says %d", s, k, taken, want)
+		}
+	}
+}
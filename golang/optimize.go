@@ -0,0 +1,29 @@
+package main
+
+// Optimize returns a copy with neighbouring tokens on the same char merged into one
+// counted repeat, `*a*a` -> `{2,}a`, `a*a` -> `{2,}a`, `a?a` -> `{1,2}a`.
+// x{a,b} followed by x{c,d} is exactly x{a+c,b+d}, so the matched strings don't
+// change, but the backtracker has one loop to unwind instead of several
+func (p *Pattern) Optimize() *Pattern {
+	out := &Pattern{src: p.src, byteMode: p.byteMode, predicates: p.predicates, iterative: p.iterative, alts: make([][]token, len(p.alts))}
+	for a, tokens := range p.alts {
+		merged := []token{}
+		for _, t := range tokens {
+			// *+a*+a never matches (the first takes every a), so possessive ones stay apart,
+			// and a group keeps its own tokens so MatchNamed still sees where it starts
+			if n := len(merged); n > 0 && !t.possessive && !merged[n-1].possessive && merged[n-1].atomKey() == t.atomKey() && merged[n-1].group == t.group {
+				last := &merged[n-1]
+				last.min += t.min
+				if last.max == -1 || t.max == -1 {
+					last.max = -1
+				} else {
+					last.max += t.max
+				}
+				continue
+			}
+			merged = append(merged, t)
+		}
+		out.alts[a] = merged
+	}
+	return out
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestOptimize(t *testing.T) {
+	p := MustCompile("*a*a")
+	opt := p.Optimize()
+	for _, s := range []string{"a", "aa", "aaa", "", "ab"} {
+		if got, want := opt.Match(s), p.Match(s); got != want {
+			t.Errorf("optimized %q on %q = %v, original says %v", p, s, got, want)
+		}
+	}
+	if len(opt.alts[0]) != 1 {
+		t.Errorf("*a*a optimized to %d tokens, want 1:\n%s", len(opt.alts[0]), opt.Debug())
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 300; trial++ {
+		p := MustCompile(randomPattern(rng, true))
+		opt := p.Optimize()
+		for k := 0; k < 20; k++ {
+			s := randomInput(rng, "abc", 8)
+			if got, want := opt.Match(s), p.Match(s); got != want {
+				t.Fatalf("optimized %q on %q = %v, original says %v", p, s, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,506 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Pattern is the regularExpression dialect compiled once so it can be matched many times.
+// matching is anchored on both ends and works on runes (bytes with WithByteMode)
+//
+//	*x        one or more x, the star applies to the NEXT char
+//	{m,n}x    m to n of x, {m} exactly m, {m,} m or more
+//	*+x       possessive star (also {m,n}+x), takes all it can and never gives back
+//	x?        zero or one x, applies to the PREVIOUS char
+//	.         any char
+//	[a-z]     any char in the class, [^a-z] any char not in it
+//	\p{name}  any char the named predicate accepts, see WithPredicates
+//	(?<n>ab)  named group, MatchNamed says what its chars took. no nesting, no `|`
+//	          inside and nothing repeats a whole group
+//	\x        x as a literal, for the special chars above and `(` `)`
+//	a|b       either side
+//
+// everything else is a literal
+type Pattern struct {
+	src      string
+	alts     [][]token // one token list per `|` alternative
+	byteMode bool      // see WithByteMode
+
+	predicates map[string]func(rune) bool // for \p{name}, see WithPredicates
+	iterative  bool                       // see WithIterativeBacktracking
+
+	nfaOnce sync.Once
+	nfa     *nfaProg // built on first MatchNFA
+}
+
+type tokenKind int
+
+const (
+	tokLiteral   tokenKind = iota // one specific rune
+	tokAny                        // `.`
+	tokClass                      // `[...]`
+	tokPredicate                  // `\p{name}`
+)
+
+type token struct {
+	kind  tokenKind
+	r     rune       // for tokLiteral
+	class *charClass // for tokClass
+	name  string     // for tokPredicate
+	pred  func(rune) bool
+	min   int // fewest repeats, 1 for a plain char
+	max   int // most repeats, -1 = unbounded (star)
+	pos   int // byte offset in the pattern, for error messages
+
+	possessive bool   // `*+` / `{m,n}+`, takes as many as it can and never gives any back
+	group      string // name of the `(?<name>...)` the token sits in, "" for none
+}
+
+// PatternError is a syntax error in a pattern, Pos is the byte offset it was found at
+type PatternError struct {
+	Pos int
+	Msg string
+}
+
+func (e *PatternError) Error() string {
+	return fmt.Sprintf("pattern: %s at offset %d", e.Msg, e.Pos)
+}
+
+// Option changes how Compile builds a Pattern
+type Option func(*Pattern)
+
+// WithByteMode works on bytes instead of runes, for binary-ish input: `.` is any
+// single byte and a multibyte char (in the pattern or the input) is several units.
+// this is how the original regularExpression indexed strings
+func WithByteMode() Option {
+	return func(p *Pattern) { p.byteMode = true }
+}
+
+// WithPredicates names the char tests `\p{name}` can use, so
+// WithPredicates(map[string]func(rune) bool{"digit": unicode.IsDigit}) allows `*\p{digit}`
+func WithPredicates(preds map[string]func(rune) bool) Option {
+	return func(p *Pattern) { p.predicates = preds }
+}
+
+// WithIterativeBacktracking swaps the recursive backtracker for one with an explicit
+// stack, same answers but no call depth growing with the input
+func WithIterativeBacktracking() Option {
+	return func(p *Pattern) { p.iterative = true }
+}
+
+func Compile(src string, opts ...Option) (*Pattern, error) {
+	p := &Pattern{src: src}
+	for _, opt := range opts {
+		opt(p)
+	}
+	alts, err := tokenize(src, p)
+	if err != nil {
+		return nil, err
+	}
+	p.alts = alts
+	return p, nil
+}
+
+// Validate only runs the tokenizer, for when you just need to know whether
+// a pattern is well formed. returns the first *PatternError found.
+// opts as for Compile, `\p{name}` needs its predicate to validate
+func Validate(src string, opts ...Option) error {
+	p := &Pattern{src: src}
+	for _, opt := range opts {
+		opt(p)
+	}
+	_, err := tokenize(src, p)
+	return err
+}
+
+// for patterns known to be valid, panics otherwise (like regexp.MustCompile)
+func MustCompile(src string, opts ...Option) *Pattern {
+	p, err := Compile(src, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (p *Pattern) String() string {
+	return p.src
+}
+
+// next unit of s and its size in bytes, a rune or a single byte in byte mode
+func (p *Pattern) decode(s string) (rune, int) {
+	if p.byteMode {
+		return rune(s[0]), 1
+	}
+	return utf8.DecodeRuneInString(s)
+}
+
+// s split into the units the matchers step over
+func (p *Pattern) units(s string) []rune {
+	if !p.byteMode {
+		return []rune(s)
+	}
+	in := make([]rune, len(s))
+	for i := 0; i < len(s); i++ {
+		in[i] = rune(s[i])
+	}
+	return in
+}
+
+// units are read with p.decode, so byte mode patterns are tokenized bytewise too
+func tokenize(src string, p *Pattern) ([][]token, error) {
+	decode := p.decode
+	alts := [][]token{}
+	tokens := []token{}
+	var prefix *repeat // pending `*` or `{m,n}`, applies to the next token
+	names := map[string]bool{}
+	group, groupPos, groupStart := "", 0, 0 // the open `(?<name>`, "" outside one
+	justClosed := false                     // last thing was a `)`
+	for pos, size := 0, 0; pos < len(src); pos += size {
+		r, n := decode(src[pos:])
+		size = n
+		afterGroup := justClosed
+		justClosed = false
+		if r == '(' {
+			name, end, err := parseGroupName(src, pos)
+			switch {
+			case err != nil:
+				return nil, err
+			case group != "":
+				return nil, &PatternError{Pos: pos, Msg: "groups can't nest"}
+			case prefix != nil:
+				return nil, &PatternError{Pos: prefix.pos, Msg: "repeat before a group, repeats apply to one char"}
+			case names[name]:
+				return nil, &PatternError{Pos: pos, Msg: fmt.Sprintf("duplicate group name %q", name)}
+			}
+			names[name] = true
+			group, groupPos, groupStart = name, pos, len(tokens)
+			size = end - pos
+			continue
+		}
+		if r == ')' {
+			switch {
+			case group == "":
+				return nil, &PatternError{Pos: pos, Msg: ") with no group open"}
+			case prefix != nil:
+				return nil, &PatternError{Pos: prefix.pos, Msg: "repeat with nothing after it"}
+			case len(tokens) == groupStart:
+				return nil, &PatternError{Pos: groupPos, Msg: "empty group"}
+			}
+			group = ""
+			justClosed = true
+			continue
+		}
+		if r == '|' {
+			if group != "" {
+				return nil, &PatternError{Pos: pos, Msg: "| inside a group"}
+			}
+			if prefix != nil {
+				return nil, &PatternError{Pos: prefix.pos, Msg: "repeat with nothing after it"}
+			}
+			alts = append(alts, tokens)
+			tokens = []token{}
+			continue
+		}
+		if r == '*' || r == '{' {
+			if prefix != nil {
+				return nil, &PatternError{Pos: pos, Msg: "repeat cannot apply to another repeat"}
+			}
+			prefix = &repeat{min: 1, max: -1, pos: pos}
+			if r == '{' {
+				rep, end, err := parseRepeat(src, pos)
+				if err != nil {
+					return nil, err
+				}
+				prefix = rep
+				size = end - pos
+			}
+			if strings.HasPrefix(src[pos+size:], "+") {
+				prefix.possessive = true
+				size++
+			}
+			continue
+		}
+		if r == '?' {
+			if prefix != nil {
+				return nil, &PatternError{Pos: pos, Msg: "? right after a repeat"}
+			}
+			if len(tokens) == 0 {
+				return nil, &PatternError{Pos: pos, Msg: "? with nothing before it"}
+			}
+			if afterGroup {
+				return nil, &PatternError{Pos: pos, Msg: "? after a group, it applies to one char"}
+			}
+			last := &tokens[len(tokens)-1]
+			if last.min != 1 || last.max != 1 {
+				return nil, &PatternError{Pos: pos, Msg: "? on a token that already repeats"}
+			}
+			last.min = 0
+			continue
+		}
+		t := token{kind: tokLiteral, r: r, min: 1, max: 1, pos: pos}
+		if r == '\\' {
+			esc, end, err := parseEscape(src, pos, p)
+			if err != nil {
+				return nil, err
+			}
+			t = esc
+			size = end - pos
+		}
+		if r == '.' {
+			t = token{kind: tokAny, min: 1, max: 1, pos: pos}
+		}
+		if r == '[' {
+			class, end, err := parseClass(src, pos, decode)
+			if err != nil {
+				return nil, err
+			}
+			t = token{kind: tokClass, class: class, min: 1, max: 1, pos: pos}
+			size = end - pos
+		}
+		if prefix != nil {
+			t.min, t.max, t.pos, t.possessive = prefix.min, prefix.max, prefix.pos, prefix.possessive
+			prefix = nil
+		}
+		t.group = group
+		tokens = append(tokens, t)
+	}
+	if prefix != nil {
+		return nil, &PatternError{Pos: prefix.pos, Msg: "repeat with nothing after it"}
+	}
+	if group != "" {
+		return nil, &PatternError{Pos: groupPos, Msg: "group with no closing )"}
+	}
+	return append(alts, tokens), nil
+}
+
+// parses the `(?<name>` at src[start], end is just past the `>`
+func parseGroupName(src string, start int) (name string, end int, err error) {
+	if !strings.HasPrefix(src[start:], "(?<") {
+		return "", 0, &PatternError{Pos: start, Msg: "( only opens a named group (?<name>...), \\( for a literal"}
+	}
+	close := strings.IndexByte(src[start:], '>')
+	if close == -1 {
+		return "", 0, &PatternError{Pos: start, Msg: "(?< with no closing >"}
+	}
+	name = src[start+3 : start+close]
+	if name == "" {
+		return "", 0, &PatternError{Pos: start, Msg: "group with no name"}
+	}
+	return name, start + close + 1, nil
+}
+
+// parses the `\` at src[start], either `\p{name}` or an escaped literal
+func parseEscape(src string, start int, p *Pattern) (t token, end int, err error) {
+	if start+1 >= len(src) {
+		return token{}, 0, &PatternError{Pos: start, Msg: "\\ at end of pattern"}
+	}
+	if !strings.HasPrefix(src[start+1:], "p{") {
+		r, n := p.decode(src[start+1:])
+		return token{kind: tokLiteral, r: r, min: 1, max: 1, pos: start}, start + 1 + n, nil
+	}
+	close := strings.IndexByte(src[start:], '}')
+	if close == -1 {
+		return token{}, 0, &PatternError{Pos: start, Msg: "\\p{ with no closing }"}
+	}
+	name := src[start+3 : start+close]
+	pred, ok := p.predicates[name]
+	if !ok {
+		return token{}, 0, &PatternError{Pos: start, Msg: fmt.Sprintf("unknown predicate %q", name)}
+	}
+	return token{kind: tokPredicate, name: name, pred: pred, min: 1, max: 1, pos: start}, start + close + 1, nil
+}
+
+// repeat is a parsed `*` or `{m,n}` waiting for the token it applies to
+type repeat struct {
+	min, max   int // max -1 = unbounded
+	pos        int
+	possessive bool
+}
+
+// parses `{m}`, `{m,}` or `{m,n}` starting at the `{` at src[start], end is just past the `}`
+func parseRepeat(src string, start int) (rep *repeat, end int, err error) {
+	close := strings.IndexByte(src[start:], '}')
+	if close == -1 {
+		return nil, 0, &PatternError{Pos: start, Msg: "repeat with no closing }"}
+	}
+	body := src[start+1 : start+close]
+	lo, hi, hasComma := strings.Cut(body, ",")
+	min, err := strconv.Atoi(lo)
+	if err != nil || min < 0 {
+		return nil, 0, &PatternError{Pos: start, Msg: fmt.Sprintf("bad repeat count %q", lo)}
+	}
+	max := min // {m}
+	if hasComma {
+		max = -1 // {m,}
+		if hi != "" {
+			max, err = strconv.Atoi(hi)
+			if err != nil || max < min {
+				return nil, 0, &PatternError{Pos: start, Msg: fmt.Sprintf("bad repeat bound %q", hi)}
+			}
+		}
+	}
+	if max == 0 {
+		return nil, 0, &PatternError{Pos: start, Msg: "repeat of zero"}
+	}
+	return &repeat{min: min, max: max, pos: start}, start + close + 1, nil
+}
+
+// charClass is a parsed `[...]`, ranges are inclusive lo/hi pairs, single chars have lo == hi
+type charClass struct {
+	negate bool // `[^...]`
+	ranges [][2]rune
+}
+
+func (c *charClass) contains(r rune) bool {
+	in := false
+	for _, rg := range c.ranges {
+		if rg[0] <= r && r <= rg[1] {
+			in = true
+			break
+		}
+	}
+	return in != c.negate
+}
+
+// parses the class starting at the `[` at src[start], end is the offset just past the `]`.
+// inside a class every char is literal except `^` right after `[` and `-` between two chars
+func parseClass(src string, start int, decode func(string) (rune, int)) (class *charClass, end int, err error) {
+	class = &charClass{}
+	pos := start + 1
+	if strings.HasPrefix(src[pos:], "^") {
+		class.negate = true
+		pos++
+	}
+	for pos < len(src) {
+		lo, n := decode(src[pos:])
+		if lo == ']' {
+			if len(class.ranges) == 0 {
+				return nil, 0, &PatternError{Pos: start, Msg: "empty class"}
+			}
+			return class, pos + n, nil
+		}
+		at := pos
+		pos += n
+		hi := lo
+		// a-z, but a trailing `-` like [a-] is just a dash
+		if strings.HasPrefix(src[pos:], "-") && pos+1 < len(src) && src[pos+1] != ']' {
+			hi, n = decode(src[pos+1:])
+			pos += 1 + n
+			if hi < lo {
+				return nil, 0, &PatternError{Pos: at, Msg: "class range out of order"}
+			}
+		}
+		class.ranges = append(class.ranges, [2]rune{lo, hi})
+	}
+	return nil, 0, &PatternError{Pos: start, Msg: "class with no closing ]"}
+}
+
+// does a single input rune satisfy the token (ignores repetition)
+func (t *token) matches(r rune) bool {
+	switch t.kind {
+	case tokAny:
+		return true
+	case tokClass:
+		return t.class.contains(r)
+	case tokPredicate:
+		return t.pred(r)
+	default:
+		return t.r == r
+	}
+}
+
+// Match reports whether the whole of s matches, backtracking into stars when needed
+func (p *Pattern) Match(s string) bool {
+	m := p.newMatcher(p.units(s))
+	for _, tokens := range p.alts {
+		if m.run(tokens) {
+			return true
+		}
+	}
+	return false
+}
+
+// matcher is one backtracking run over an input, the extra fields are for
+// callers that want more than a bool out of it
+type matcher struct {
+	in        []rune
+	iterative bool // matchIter instead of the recursive match
+
+	onFail func(i int, t *token) // if set, called where an attempt died, t is nil for leftover input
+	prefix bool                  // input may continue after the pattern ends
+	end    int                   // where the match ended
+	took   []int                 // if set (len = tokens in the alternative), took[k] = units token k used in the match
+
+	budget   int  // max steps (token attempts) before giving up, 0 = no limit
+	steps    int  // steps taken so far, across every alternative run
+	exceeded bool // ran out of budget, the false it returned means nothing
+}
+
+func (p *Pattern) newMatcher(in []rune) *matcher {
+	return &matcher{in: in, iterative: p.iterative}
+}
+
+// matches one alternative from the start of the input
+func (m *matcher) run(tokens []token) bool {
+	if m.iterative {
+		return m.matchIter(tokens)
+	}
+	return m.match(tokens, 0)
+}
+
+// counts one token attempt, false once the budget is used up
+func (m *matcher) step() bool {
+	if m.budget == 0 {
+		return true
+	}
+	if m.steps >= m.budget {
+		m.exceeded = true
+		return false
+	}
+	m.steps++
+	return true
+}
+
+func (m *matcher) match(tokens []token, i int) bool {
+	if !m.step() {
+		return false
+	}
+	if len(tokens) == 0 {
+		if m.prefix || i == len(m.in) {
+			m.end = i
+			return true
+		}
+		if m.onFail != nil {
+			m.onFail(i, nil)
+		}
+		return false
+	}
+	t := &tokens[0]
+	// take as many as the token allows, then give back one at a time
+	n := 0
+	for i+n < len(m.in) && (t.max == -1 || n < t.max) && t.matches(m.in[i+n]) {
+		n++
+	}
+	if n < t.min && m.onFail != nil {
+		m.onFail(i+n, t)
+	}
+	fewest := t.min
+	if t.possessive {
+		fewest = n // keeps everything it took, nothing to retry
+	}
+	for ; n >= t.min && n >= fewest; n-- {
+		if m.match(tokens[1:], i+n) {
+			if m.took != nil {
+				m.took[len(m.took)-len(tokens)] = n
+			}
+			return true
+		}
+		if m.exceeded {
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// the regularExpression examples from main, shared by the tests of every matcher
+var matchCases = []struct {
+	s, pattern string
+	want       bool
+}{
+	{"abb", "abc", false},
+	{"abc", "a.c", true},
+	{"abd", "a.c", false},
+	{"abd", "...", true},
+	{"abbbbcyz", "a*bc.z", true},
+	{"abbbbcddyz", "a*bc*d.z", true},
+	{"abbbbcddyz", "a*bc*d", false},
+	{"abbbb", "a*bc*d", false},
+	{"aaaa", "*a", true},
+	{"abc", "*.", true},
+	{"abcd", "a*bc", false},
+	{"ab", "a*bc", false},
+	{"b", "*a", false},
+	{"", "", true},
+	{"", "*a", false},
+	{"aaabbbcc", "*a*b*c", true},
+}
+
+func TestMatch(t *testing.T) {
+	for _, c := range matchCases {
+		if got := MustCompile(c.pattern).Match(c.s); got != c.want {
+			t.Errorf("%q.Match(%q) = %v, want %v", c.pattern, c.s, got, c.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, src := range []string{"", "a*bc.z", "*[a-z]X", "a{2,3}bc?|d"} {
+		if err := Validate(src); err != nil {
+			t.Errorf("Validate(%q) = %v, want nil", src, err)
+		}
+	}
+
+	bad := []struct {
+		src string
+		pos int
+	}{
+		{"ab*", 2},  // star with nothing after it
+		{"x[ab", 1}, // unclosed class
+		{"a{2", 1},  // unclosed repeat
+		{"ab\\", 2}, // trailing backslash
+		{"?a", 0},   // nothing for ? to apply to
+	}
+	for _, c := range bad {
+		err := Validate(c.src)
+		var perr *PatternError
+		if !errors.As(err, &perr) {
+			t.Errorf("Validate(%q) = %v, want a *PatternError", c.src, err)
+			continue
+		}
+		if perr.Pos != c.pos {
+			t.Errorf("Validate(%q) error at %d, want %d (%v)", c.src, perr.Pos, c.pos, err)
+		}
+	}
+}
+
+func TestOptional(t *testing.T) {
+	p := MustCompile("ab?c")
+	for s, want := range map[string]bool{"abc": true, "ac": true, "abbc": false, "ab": false, "c": false} {
+		if got := p.Match(s); got != want {
+			t.Errorf("%q.Match(%q) = %v, want %v", p, s, got, want)
+		}
+	}
+}
+
+func TestNegatedClass(t *testing.T) {
+	cases := []struct {
+		s, pattern string
+		want       bool
+	}{
+		{"x", "[^a-c]", true},
+		{"b", "[^a-c]", false},
+		{"", "[^a-c]", false},
+		{"abc,def", "*[^,],def", true}, // the run stops at the comma
+		{"ab,c,def", "*[^,],def", false},
+		{"a,b", "*[^,]", false},
+		{"é", "[^a-c]", true}, // one rune, not two bytes
+	}
+	for _, c := range cases {
+		if got := regularExpression(c.s, c.pattern); got != c.want {
+			t.Errorf("regularExpression(%q, %q) = %v, want %v", c.s, c.pattern, got, c.want)
+		}
+	}
+}
+
+func TestStarClass(t *testing.T) {
+	cases := []struct {
+		s, pattern string
+		want       bool
+	}{
+		{"abcX", "*[a-z]X", true},
+		{"abc", "*[a-z]X", false},
+		{"X", "*[a-z]X", false}, // the star needs at least one
+		{"abcc", "*[a-z]c", true},
+		{"aBcX", "*[a-z]X", false},
+	}
+	for _, c := range cases {
+		if got := regularExpression(c.s, c.pattern); got != c.want {
+			t.Errorf("regularExpression(%q, %q) = %v, want %v", c.s, c.pattern, got, c.want)
+		}
+	}
+}
+
+func TestByteMode(t *testing.T) {
+	runes, bytes := MustCompile("a.b"), MustCompile("a.b", WithByteMode())
+	if !runes.Match("aéb") || bytes.Match("aéb") {
+		t.Error(`"a.b" on "aéb": rune mode should take é as one unit, byte mode as two`)
+	}
+	if !MustCompile("a..b", WithByteMode()).Match("aéb") || MustCompile("a..b").Match("aéb") {
+		t.Error(`"a..b" on "aéb": byte mode should take é as two units, rune mode as one`)
+	}
+	// an é in a byte mode pattern is its two bytes in a row
+	if !MustCompile("*é", WithByteMode()).Match("é") || !MustCompile("é", WithByteMode()).Match("é") {
+		t.Error("byte mode é doesn't match itself")
+	}
+	if !MustCompile("a.", WithByteMode()).Match("a\xff") || !MustCompile("a.").Match("a\xff") {
+		t.Error("a stray byte should be one unit in both modes")
+	}
+}
+
+func TestPossessive(t *testing.T) {
+	// nothing after the star could use a given back a, so both agree
+	for _, s := range []string{"a", "aaa", "", "ab", "b"} {
+		if got, want := MustCompile("*+a").Match(s), MustCompile("*a").Match(s); got != want {
+			t.Errorf("*+a on %q = %v, *a says %v", s, got, want)
+		}
+	}
+	if !MustCompile("*+ab").Match("aab") {
+		t.Error("*+ab should match aab, the b isn't an a")
+	}
+	// the first possessive star takes every a and the second gets none
+	for _, s := range []string{"aa", "aaa"} {
+		if !MustCompile("*a*a").Match(s) || MustCompile("*+a*+a").Match(s) {
+			t.Errorf("on %q *a*a should match and *+a*+a shouldn't", s)
+		}
+	}
+	if MustCompile("{1,3}+aa").Match("aaa") || !MustCompile("{1,3}aa").Match("aaa") {
+		t.Error("{1,3}+aa shouldn't give back an a for the last one")
+	}
+}
+
+func TestPredicate(t *testing.T) {
+	vowel := func(r rune) bool { return strings.ContainsRune("aeiou", r) }
+	p, err := Compile(`*\p{vowel}`, WithPredicates(map[string]func(rune) bool{"vowel": vowel}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for s, want := range map[string]bool{"aeiou": true, "a": true, "aeixou": false, "": false} {
+		if got := p.Match(s); got != want {
+			t.Errorf("%q.Match(%q) = %v, want %v", p, s, got, want)
+		}
+	}
+	var perr *PatternError
+	if _, err := Compile(`\p{vowel}`); !errors.As(err, &perr) {
+		t.Errorf("unregistered predicate: err = %v, want a *PatternError", err)
+	}
+}
+
+// random valid pattern over a and b: literals, `.`, classes, stars, bounded repeats,
+// `?` and `|`. possessive adds `*+` / `{m,n}+`
+func randomPattern(rng *rand.Rand, possessive bool) string {
+	atoms := []string{"a", "b", ".", "[ab]", "[^a]"}
+	for {
+		var sb strings.Builder
+		for alt := rng.Intn(2); alt >= 0; alt-- {
+			for n := rng.Intn(4); n >= 0; n-- {
+				switch rng.Intn(4) {
+				case 0:
+					sb.WriteString("*")
+				case 1:
+					sb.WriteString([]string{"{0,2}", "{2}", "{1,}"}[rng.Intn(3)])
+				}
+				if possessive && rng.Intn(4) == 0 && strings.ContainsAny(sb.String()[max(0, sb.Len()-1):], "*}") {
+					sb.WriteString("+")
+				}
+				sb.WriteString(atoms[rng.Intn(len(atoms))])
+				if rng.Intn(5) == 0 {
+					sb.WriteString("?")
+				}
+			}
+			if alt > 0 {
+				sb.WriteString("|")
+			}
+		}
+		if _, err := Compile(sb.String()); err == nil {
+			return sb.String()
+		}
+	}
+}
+
+// random string of up to maxLen chars from alphabet
+func randomInput(rng *rand.Rand, alphabet string, maxLen int) string {
+	rs := []rune(alphabet)
+	b := make([]rune, rng.Intn(maxLen+1))
+	for i := range b {
+		b[i] = rs[rng.Intn(len(rs))]
+	}
+	return string(b)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// PoolMetrics is what one RunPoolMetrics call did
+type PoolMetrics struct {
+	Tasks          int // len(inputs)
+	Successes      int // fn returned nil
+	Failures       int // fn returned an error, or never ran because ctx was done
+	MaxConcurrency int // most fn calls seen running at the same time
+}
+
+// RunPool calls fn on every input, at most `concurrency` at a time (via Throttle),
+// results[i] / errs[i] belong to inputs[i]. once ctx is done the inputs that
+// haven't started are skipped with ctx.Err() as their error
+func RunPool[T, R any](ctx context.Context, inputs []T, concurrency int, fn func(context.Context, T) (R, error)) ([]R, []error) {
+	results, errs, _ := RunPoolMetrics(ctx, inputs, concurrency, fn)
+	return results, errs
+}
+
+// RunPool plus PoolMetrics
+func RunPoolMetrics[T, R any](ctx context.Context, inputs []T, concurrency int, fn func(context.Context, T) (R, error)) ([]R, []error, PoolMetrics) {
+	results := make([]R, len(inputs))
+	errs := make([]error, len(inputs))
+	throttle := NewThrottle(concurrency)
+	var running, peak atomic.Int64
+
+	var wg sync.WaitGroup
+	for i, in := range inputs {
+		if err := throttle.Acquire(ctx); err != nil {
+			for j := i; j < len(inputs); j++ {
+				errs[j] = err
+			}
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer throttle.Release()
+
+			now := running.Add(1)
+			for { // raise peak to now unless another goroutine already went higher
+				old := peak.Load()
+				if now <= old || peak.CompareAndSwap(old, now) {
+					break
+				}
+			}
+			results[i], errs[i] = fn(ctx, in) // each goroutine owns its own index, no lock needed
+			running.Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	metrics := PoolMetrics{Tasks: len(inputs), MaxConcurrency: int(peak.Load())}
+	for _, err := range errs {
+		if err != nil {
+			metrics.Failures++
+		} else {
+			metrics.Successes++
+		}
+	}
+	return results, errs, metrics
+}
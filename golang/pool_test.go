@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunPoolMetricsConcurrency(t *testing.T) {
+	for _, limit := range []int{1, 3, 8} {
+		inputs := make([]int, 4*limit)
+		for i := range inputs {
+			inputs[i] = i
+		}
+		// the first `limit` calls wait until all of them are running, so the pool
+		// is held at its limit at least once
+		var started atomic.Int64
+		full := make(chan struct{})
+		var once sync.Once
+		results, errs, m := RunPoolMetrics(context.Background(), inputs, limit, func(_ context.Context, in int) (int, error) {
+			if started.Add(1) == int64(limit) {
+				once.Do(func() { close(full) })
+			}
+			select {
+			case <-full:
+			case <-time.After(time.Second):
+				return 0, errors.New("pool never reached its limit")
+			}
+			if in%5 == 4 {
+				return 0, errors.New("fails")
+			}
+			return 2 * in, nil
+		})
+
+		if m.MaxConcurrency != limit {
+			t.Errorf("limit %d: MaxConcurrency = %d, want exactly the limit", limit, m.MaxConcurrency)
+		}
+		failures := 0
+		for i, in := range inputs {
+			if in%5 == 4 {
+				failures++
+				if errs[i] == nil {
+					t.Errorf("limit %d: input %d should have failed", limit, in)
+				}
+			} else if errs[i] != nil || results[i] != 2*in {
+				t.Errorf("limit %d: input %d gave %d, %v", limit, in, results[i], errs[i])
+			}
+		}
+		if m.Tasks != len(inputs) || m.Failures != failures || m.Successes != len(inputs)-failures {
+			t.Errorf("limit %d: metrics %+v, want %d tasks, %d failures", limit, m, len(inputs), failures)
+		}
+	}
+}
+
+func TestRunPoolMetricsNeverExceedsLimit(t *testing.T) {
+	const limit = 4
+	inputs := make([]int, 100)
+	var running, peak atomic.Int64
+	_, _, m := RunPoolMetrics(context.Background(), inputs, limit, func(_ context.Context, _ int) (int, error) {
+		now := running.Add(1)
+		defer running.Add(-1)
+		for old := peak.Load(); now > old && !peak.CompareAndSwap(old, now); old = peak.Load() {
+		}
+		time.Sleep(time.Millisecond)
+		return 0, nil
+	})
+	if m.MaxConcurrency > limit || peak.Load() > limit {
+		t.Errorf("MaxConcurrency %d, seen %d at once, limit %d", m.MaxConcurrency, peak.Load(), limit)
+	}
+	if int64(m.MaxConcurrency) < peak.Load() {
+		t.Errorf("MaxConcurrency %d, but fn saw %d at once", m.MaxConcurrency, peak.Load())
+	}
+}
+
+func TestRunPoolCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, errs, m := RunPoolMetrics(ctx, []int{1, 2, 3}, 2, func(_ context.Context, in int) (int, error) {
+		return in, nil
+	})
+	for i, err := range errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("input %d: err %v, want context.Canceled", i, err)
+		}
+	}
+	if m.Failures != 3 || m.MaxConcurrency != 0 {
+		t.Errorf("metrics %+v, want 3 failures and nothing run", m)
+	}
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"strings"
+)
+
+// same cancellation as superReducedString but returns the residual as is,
+// "" instead of "Empty String" when everything cancels
+func SuperReducedStringRaw(s string) string {
+	stack := []rune{}
+	for _, char := range s {
+		if len(stack) > 0 && stack[len(stack)-1] == char {
+			stack = stack[:len(stack)-1] // pair cancels
+		} else {
+			stack = append(stack, char)
+		}
+	}
+	return string(stack)
+}
+
+// fewest single char deletions (made any time between cancellations) that let the rest
+// cancel. not the core length: "aba" reduces to itself but deleting the b leaves "aa".
+// a char that isn't deleted cancels with an equal one once everything between them is
+// gone, so gone[i][j] = fewest deletions to clear runes i..j-1. O(n^3)
+func MinDeletionsToEmpty(s string) int {
+	rs := []rune(s)
+	n := len(rs)
+	gone := make([][]int, n+1)
+	for i := range gone {
+		gone[i] = make([]int, n+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j <= n; j++ {
+			best := 1 + gone[i+1][j]
+			for k := i + 1; k < j; k++ {
+				if rs[k] == rs[i] {
+					best = min(best, gone[i+1][k]+gone[k+1][j])
+				}
+			}
+			gone[i][j] = best
+		}
+	}
+	return gone[0][n]
+}
+
+// RuneRun is one stretch of the same rune repeated Count times
+type RuneRun struct {
+	R     rune
+	Count int
+}
+
+// "aaabbc" -> [{a 3} {b 2} {c 1}], works on runes so multibyte chars count once
+func RuneRuns(s string) []RuneRun {
+	runs := []RuneRun{}
+	for _, r := range s {
+		if len(runs) > 0 && runs[len(runs)-1].R == r {
+			runs[len(runs)-1].Count++
+		} else {
+			runs = append(runs, RuneRun{R: r, Count: 1})
+		}
+	}
+	return runs
+}
+
+// superReducedString generalised: an adjacent pair a,b cancels when cancels(a, b).
+// stack based, always cancels the leftmost pair it can, which is fine for aa-style
+// rules but for rules like ab / bc the order matters ("abc" can end as "c" or "a")
+func ReduceByPairs(s string, cancels func(a, b rune) bool) string {
+	stack := []rune{}
+	for _, char := range s {
+		if len(stack) > 0 && cancels(stack[len(stack)-1], char) {
+			stack = stack[:len(stack)-1]
+		} else {
+			stack = append(stack, char)
+		}
+	}
+	return string(stack)
+}
+
+// smallest residual over every cancellation order, shortest first then
+// lexicographically smallest. tries every order (memoised on the remaining string),
+// so exponential in the worst case, meant for short inputs
+func ReduceByPairsCanonical(s string, cancels func(a, b rune) bool) string {
+	memo := map[string]string{}
+	var best func(cur string) string
+	best = func(cur string) string {
+		if res, ok := memo[cur]; ok {
+			return res
+		}
+		runes := []rune(cur)
+		res := cur
+		for i := 0; i+1 < len(runes); i++ {
+			if !cancels(runes[i], runes[i+1]) {
+				continue
+			}
+			cand := best(string(runes[:i]) + string(runes[i+2:]))
+			if len(cand) < len(res) || len(cand) == len(res) && cand < res {
+				res = cand
+			}
+		}
+		memo[cur] = res
+		return res
+	}
+	return best(s)
+}
+
+// canonical residual for the superReducedString rule (equal neighbours cancel).
+// that rule is confluent, every order ends at the same string, so the stack
+// reduction already is the canonical form and the exhaustive search isn't needed
+func SuperReducedCanonical(s string) string {
+	return SuperReducedStringRaw(s)
+}
+
+// longest run of one char that cancels during reduction, counting only the part
+// that goes (an odd run leaves one behind). runs are taken as the stack sees them,
+// so in "abba" the two a's meet once bb is gone and count as a run of 2.
+// "aaaabb" -> 4, "aaabb" -> 2
+func LongestCancelledRun(s string) int {
+	longest := 0
+	stack := []rune{} // leftover chars, one per run that had an odd length
+	for _, run := range RuneRuns(s) {
+		n := run.Count
+		if len(stack) > 0 && stack[len(stack)-1] == run.R {
+			stack = stack[:len(stack)-1] // leftover joins this run
+			n++
+		}
+		longest = max(longest, n-n%2)
+		if n%2 == 1 {
+			stack = append(stack, run.R)
+		}
+	}
+	return longest
+}
+
+// superReducedString over many inputs, `concurrency` at a time, out[i] is for inputs[i]
+func SuperReduceBatch(inputs []string, concurrency int) []string {
+	out, _ := RunPool(context.Background(), inputs, concurrency, func(_ context.Context, s string) (string, error) {
+		return superReducedString(s), nil
+	})
+	return out
+}
+
+// ErrResidualTooLong is what SuperReduceMax wraps when too much is left over
+var ErrResidualTooLong = errors.New("reduced string too long")
+
+// SuperReducedStringRaw, but an error if more than maxResult chars (runes) survive.
+// each char still to come can cancel at most one on the stack, so it gives up as soon
+// as the stack is too big for the rest of the input to bring it under maxResult
+func SuperReduceMax(s string, maxResult int) (string, error) {
+	runes := []rune(s)
+	stack := []rune{}
+	for i, char := range runes {
+		if len(stack) > 0 && stack[len(stack)-1] == char {
+			stack = stack[:len(stack)-1]
+		} else {
+			stack = append(stack, char)
+		}
+		if left := len(runes) - i - 1; len(stack)-left > maxResult {
+			return "", fmt.Errorf("%w: more than %d chars would be left, gave up at rune %d", ErrResidualTooLong, maxResult, i)
+		}
+	}
+	return string(stack), nil
+}
+
+// RewriteRule replaces From with To, ReduceByPairs' cancellations are rules like {"aa", ""}
+type RewriteRule struct {
+	From, To string
+}
+
+// ErrNonTerminating is what ReduceByRules returns when the rules would never stop
+var ErrNonTerminating = errors.New("rewrite rules don't terminate")
+
+// longest a ReduceByRules run may go before it counts as not terminating, rules that
+// only ever shrink the string stop long before (one step per char at most)
+const maxRewriteSteps = 10_000
+
+// applies rules until none match, each step rewrites the leftmost occurrence of the first
+// rule (in order) that occurs at all. rules that can undo each other (ab->ba, ba->ab) or
+// grow forever (a->aa) are caught, a repeated string or maxRewriteSteps steps gives
+// ErrNonTerminating. a rule with an empty From would always match, that's an error too
+func ReduceByRules(s string, rules []RewriteRule) (string, error) {
+	for _, rule := range rules {
+		if rule.From == "" {
+			return "", errors.New("ReduceByRules: rule with empty From")
+		}
+	}
+	// strings seen so far, by hash so a long run doesn't keep every version around
+	seed := maphash.MakeSeed()
+	seen := map[uint64]bool{maphash.String(seed, s): true}
+	for step := 0; ; step++ {
+		applied := false
+		for _, rule := range rules {
+			if i := strings.Index(s, rule.From); i != -1 {
+				s = s[:i] + rule.To + s[i+len(rule.From):]
+				applied = true
+				break
+			}
+		}
+		if !applied {
+			return s, nil
+		}
+		h := maphash.String(seed, s)
+		if seen[h] || step >= maxRewriteSteps {
+			return s, fmt.Errorf("%w: still rewriting after %d steps", ErrNonTerminating, step+1)
+		}
+		seen[h] = true
+	}
+}
+
+// SuperReducedStringRaw plus how many pairs it cancelled on the way
+func SuperReducedStringCount(s string) (residual string, pairs int) {
+	residual = SuperReducedStringRaw(s)
+	return residual, (len([]rune(s)) - len([]rune(residual))) / 2
+}
+
+// hist[n] = how many inputs had n pairs cancelled
+func ReductionStats(inputs []string) map[int]int {
+	hist := map[int]int{}
+	for _, s := range inputs {
+		_, pairs := SuperReducedStringCount(s)
+		hist[pairs]++
+	}
+	return hist
+}
+
+// what's left of a next to what's left of b (SuperReducedStringRaw), as their common
+// prefix and suffix around the middle that differs, git --word-diff style:
+// "ab[-c-]{+xy+}de" is a leaving "abcde" and b leaving "abxyde". a side with nothing
+// in the middle is left out, equal residuals come back as they are
+func ReductionDiff(a, b string) string {
+	ra, rb := []rune(SuperReducedStringRaw(a)), []rune(SuperReducedStringRaw(b))
+	pre := 0
+	for pre < len(ra) && pre < len(rb) && ra[pre] == rb[pre] {
+		pre++
+	}
+	suf := 0 // can't reach back into the prefix, "aa" vs "aaa" differs by one a
+	for suf < len(ra)-pre && suf < len(rb)-pre && ra[len(ra)-1-suf] == rb[len(rb)-1-suf] {
+		suf++
+	}
+
+	var sb strings.Builder
+	sb.WriteString(string(ra[:pre]))
+	if mid := ra[pre : len(ra)-suf]; len(mid) > 0 {
+		sb.WriteString("[-" + string(mid) + "-]")
+	}
+	if mid := rb[pre : len(rb)-suf]; len(mid) > 0 {
+		sb.WriteString("{+" + string(mid) + "+}")
+	}
+	sb.WriteString(string(ra[len(ra)-suf:]))
+	return sb.String()
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// fewest deletions by trying every order of delete-one / cancel-one-pair, small s only
+func minDeletionsBrute(s string, memo map[string]int) int {
+	if s == "" {
+		return 0
+	}
+	if n, ok := memo[s]; ok {
+		return n
+	}
+	best := len(s)
+	for i := 0; i < len(s); i++ {
+		best = min(best, 1+minDeletionsBrute(s[:i]+s[i+1:], memo))
+		if i+1 < len(s) && s[i] == s[i+1] {
+			best = min(best, minDeletionsBrute(s[:i]+s[i+2:], memo))
+		}
+	}
+	memo[s] = best
+	return best
+}
+
+func TestMinDeletionsToEmpty(t *testing.T) {
+	cases := []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"aa", 0},
+		{"abc", 3},
+		{"aba", 1},
+		{"aaabccddd", 3},
+		{"abcba", 1},
+		{"abab", 2},
+	}
+	for _, c := range cases {
+		if got := MinDeletionsToEmpty(c.s); got != c.want {
+			t.Errorf("MinDeletionsToEmpty(%q) = %d, want %d", c.s, got, c.want)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	memo := map[string]int{}
+	for trial := 0; trial < 500; trial++ {
+		b := make([]byte, rng.Intn(9))
+		for i := range b {
+			b[i] = 'a' + byte(rng.Intn(3))
+		}
+		s := string(b)
+		got := MinDeletionsToEmpty(s)
+		if want := minDeletionsBrute(s, memo); got != want {
+			t.Fatalf("MinDeletionsToEmpty(%q) = %d, brute force says %d", s, got, want)
+		}
+		if core := len(SuperReducedStringRaw(s)); got > core {
+			t.Fatalf("MinDeletionsToEmpty(%q) = %d, more than the %d char core", s, got, core)
+		}
+	}
+}
+
+func TestRuneRuns(t *testing.T) {
+	cases := []struct {
+		s    string
+		want []RuneRun
+	}{
+		{"aaabbc", []RuneRun{{'a', 3}, {'b', 2}, {'c', 1}}},
+		{"", []RuneRun{}},
+		{"ééa", []RuneRun{{'é', 2}, {'a', 1}}},
+		{"abab", []RuneRun{{'a', 1}, {'b', 1}, {'a', 1}, {'b', 1}}},
+	}
+	for _, c := range cases {
+		if got := RuneRuns(c.s); !slices.Equal(got, c.want) {
+			t.Errorf("RuneRuns(%q) = %v, want %v", c.s, got, c.want)
+		}
+	}
+}
+
+func TestReduceByPairsCanonical(t *testing.T) {
+	// ab and bc cancel, so "abc" ends as "c" or "a" depending on which goes first
+	pairs := map[[2]rune]bool{{'a', 'b'}: true, {'b', 'c'}: true}
+	cancels := func(a, b rune) bool { return pairs[[2]rune{a, b}] }
+	cases := []struct {
+		s, stack, canonical string
+	}{
+		{"abc", "c", "a"},
+		{"abcc", "cc", "ac"},
+		{"aabbc", "c", "a"}, // ab inside ab, then c is left, or bc first and an a is
+		{"cab", "c", "c"},
+		{"abbc", "", ""}, // "abbc" -> "bc" -> "" on the stack, "ac" the other way
+	}
+	for _, c := range cases {
+		if got := ReduceByPairs(c.s, cancels); got != c.stack {
+			t.Errorf("ReduceByPairs(%q) = %q, want %q", c.s, got, c.stack)
+		}
+		if got := ReduceByPairsCanonical(c.s, cancels); got != c.canonical {
+			t.Errorf("ReduceByPairsCanonical(%q) = %q, want %q", c.s, got, c.canonical)
+		}
+	}
+
+	// equal neighbours cancelling is confluent, the stack is already canonical
+	same := func(a, b rune) bool { return a == b }
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 300; trial++ {
+		s := randomInput(rng, "abc", 10)
+		if got, want := SuperReducedCanonical(s), ReduceByPairsCanonical(s, same); got != want {
+			t.Fatalf("SuperReducedCanonical(%q) = %q, exhaustive search says %q", s, got, want)
+		}
+	}
+}
+
+// a string of distinct chars has no equal neighbours, nothing cancels
+func TestSuperReducedStringDistinctChars(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for trial := 0; trial < 100; trial++ {
+		s := string(Deduplicate([]rune(randomInput(rng, "abcdefgh", 12))))
+		if got := SuperReducedStringRaw(s); got != s {
+			t.Fatalf("SuperReducedStringRaw(%q) = %q, want it unchanged", s, got)
+		}
+	}
+}
+
+func TestLongestCancelledRun(t *testing.T) {
+	for s, want := range map[string]int{"aaaabb": 4, "aaabb": 2, "abba": 2, "abc": 0, "": 0, "baab": 2, "abbbba": 4} {
+		if got := LongestCancelledRun(s); got != want {
+			t.Errorf("LongestCancelledRun(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestSuperReduceBatch(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	inputs := make([]string, 300)
+	for i := range inputs {
+		inputs[i] = randomInput(rng, "abc", 30)
+	}
+	for _, concurrency := range []int{1, 4, 1000} {
+		got := SuperReduceBatch(inputs, concurrency)
+		if len(got) != len(inputs) {
+			t.Fatalf("concurrency %d: %d results for %d inputs", concurrency, len(got), len(inputs))
+		}
+		for i, s := range inputs {
+			if want := superReducedString(s); got[i] != want {
+				t.Fatalf("concurrency %d: input %q gave %q, want %q", concurrency, s, got[i], want)
+			}
+		}
+	}
+}
+
+// concurrency 1 is the sequential baseline, the others should speed up with the cores available
+func BenchmarkSuperReduceBatch(b *testing.B) {
+	rng := rand.New(rand.NewSource(5))
+	inputs := make([]string, 2000)
+	for i := range inputs {
+		inputs[i] = randomInput(rng, "ab", 2000)
+	}
+	for _, concurrency := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				SuperReduceBatch(inputs, concurrency)
+			}
+		})
+	}
+}
+
+func TestSuperReduceMax(t *testing.T) {
+	got, err := SuperReduceMax("aabbccddeeffa", 1) // everything but the last a cancels
+	if err != nil || got != "a" {
+		t.Errorf(`SuperReduceMax("aabbccddeeffa", 1) = %q, %v, want "a", nil`, got, err)
+	}
+	if _, err := SuperReduceMax("abcdefgh", 3); !errors.Is(err, ErrResidualTooLong) {
+		t.Errorf(`SuperReduceMax("abcdefgh", 3) err = %v, want ErrResidualTooLong`, err)
+	}
+
+	rng := rand.New(rand.NewSource(6))
+	for trial := 0; trial < 500; trial++ {
+		s := randomInput(rng, "abc", 12)
+		limit := rng.Intn(6)
+		got, err := SuperReduceMax(s, limit)
+		want := SuperReducedStringRaw(s)
+		if len(want) <= limit && (err != nil || got != want) || len(want) > limit && !errors.Is(err, ErrResidualTooLong) {
+			t.Fatalf("SuperReduceMax(%q, %d) = %q, %v; residual is %q", s, limit, got, err, want)
+		}
+	}
+}
+
+func TestReduceByRules(t *testing.T) {
+	// equal neighbours cancelling as rules, confluent so the order can't matter
+	cancel := []RewriteRule{{"aa", ""}, {"bb", ""}, {"cc", ""}}
+	rng := rand.New(rand.NewSource(2))
+	for trial := 0; trial < 300; trial++ {
+		s := randomInput(rng, "abc", 12)
+		if got, err := ReduceByRules(s, cancel); err != nil || got != SuperReducedStringRaw(s) {
+			t.Fatalf("ReduceByRules(%q, cancel) = %q, %v, want %q", s, got, err, SuperReducedStringRaw(s))
+		}
+	}
+
+	if got, err := ReduceByRules("abab", []RewriteRule{{"ab", "c"}, {"cc", "d"}}); err != nil || got != "d" {
+		t.Errorf("ReduceByRules(abab) = %q, %v, want d", got, err)
+	}
+
+	loops := map[string][]RewriteRule{
+		"swap":  {{"ab", "ba"}, {"ba", "ab"}},
+		"grows": {{"a", "aa"}},
+	}
+	for name, rules := range loops {
+		if _, err := ReduceByRules("ab", rules); !errors.Is(err, ErrNonTerminating) {
+			t.Errorf("%s rules: err = %v, want ErrNonTerminating", name, err)
+		}
+	}
+	if _, err := ReduceByRules("ab", []RewriteRule{{"", "x"}}); err == nil || errors.Is(err, ErrNonTerminating) {
+		t.Errorf("empty From: err = %v, want a rule error", err)
+	}
+}
+
+func TestReductionStats(t *testing.T) {
+	inputs := []string{
+		"abc",      // 0 pairs
+		"",         // 0
+		"aab",      // 1
+		"abba",     // 2, bb then aa
+		"aabbcc",   // 3
+		"aaabccdd", // 3, one a is left over
+		"xx",       // 1
+	}
+	want := map[int]int{0: 2, 1: 2, 2: 1, 3: 2}
+	if got := ReductionStats(inputs); !maps.Equal(got, want) {
+		t.Errorf("ReductionStats(%q) = %v, want %v", inputs, got, want)
+	}
+	if got := ReductionStats(nil); len(got) != 0 {
+		t.Errorf("ReductionStats(nil) = %v, want empty", got)
+	}
+}
+
+func TestReductionDiff(t *testing.T) {
+	cases := []struct {
+		a, b, want string
+	}{
+		{"abcde", "abxyde", "ab[-c-]{+xy+}de"},
+		{"abccbde", "ade", "ade"}, // both leave "ade"
+		{"abc", "abd", "ab[-c-]{+d+}"},
+		{"abde", "abxxcde", "ab{+c+}de"}, // the xx cancel, only the c is new
+		{"abcde", "ae", "a[-bcd-]e"},
+		{"aa", "aaa", "{+a+}"},
+		{"", "", ""},
+	}
+	for _, c := range cases {
+		if got := ReductionDiff(c.a, c.b); got != c.want {
+			t.Errorf("ReductionDiff(%q, %q) = %q, want %q", c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import "errors"
+
+// first `limit` positions of target inside the first n runes of s repeated forever.
+// only the offsets inside one copy of s are scanned, every later hit is
+// copy*len(s) + offset so the repetition is never built
+func RepeatedCharIndices(s string, n int64, target rune, limit int) []int64 {
+	runes := []rune(s)
+	offsets := []int64{} // where target sits inside one copy of s
+	for i, r := range runes {
+		if r == target {
+			offsets = append(offsets, int64(i))
+		}
+	}
+	if len(offsets) == 0 || limit <= 0 {
+		return []int64{}
+	}
+
+	lenS := int64(len(runes))
+	res := []int64{}
+	for k := 0; k < limit; k++ {
+		copyNum := int64(k / len(offsets))
+		pos := copyNum*lenS + offsets[k%len(offsets)]
+		if pos >= n {
+			break // past the first n chars, fewer than limit hits exist
+		}
+		res = append(res, pos)
+	}
+	return res
+}
+
+// smallest p where s is its first p chars repeated, len(s) when it isn't periodic.
+// uses the KMP failure function: fail[i] = longest proper prefix of s[:i+1] that is also a suffix
+func RepeatPeriod(s string) int {
+	n := len(s)
+	if n == 0 {
+		return 0
+	}
+	fail := make([]int, n)
+	for i, k := 1, 0; i < n; i++ {
+		for k > 0 && s[i] != s[k] {
+			k = fail[k-1]
+		}
+		if s[i] == s[k] {
+			k++
+		}
+		fail[i] = k
+	}
+	p := n - fail[n-1]
+	if n%p == 0 {
+		return p // "abab" -> 2
+	}
+	return n // "abcac" -> 5
+}
+
+// non-overlapping (leftmost first) occurrences of sub in the first n chars of s repeated
+// forever, matches that run across the join between two copies count too.
+// byte based like repeatedString.
+// where the next match starts only depends on pos % len(s), so once a pos % len(s)
+// comes round again the matches in between repeat and whole cycles are skipped at once
+func RepeatedSubstringCount(s string, n int64, sub string) int64 {
+	lenS, lenSub := int64(len(s)), int64(len(sub))
+	if lenS == 0 || lenSub == 0 || n < lenSub {
+		return 0
+	}
+
+	// occurs[o] = sub starts at offset o of the infinite string, may wrap past the end of s
+	occurs := make([]bool, lenS)
+	found := false
+	for o := int64(0); o < lenS; o++ {
+		occurs[o] = true
+		for j := int64(0); j < lenSub; j++ {
+			if sub[j] != s[(o+j)%lenS] {
+				occurs[o] = false
+				break
+			}
+		}
+		found = found || occurs[o]
+	}
+	if !found {
+		return 0
+	}
+
+	// dist[o] = how far from offset o the next match starts (0 if one starts at o)
+	dist := make([]int64, lenS)
+	next := int64(-1)
+	for i := 2*lenS - 1; i >= 0; i-- { // two passes so offsets near the end see the wrap
+		o := i % lenS
+		if occurs[o] {
+			next = i
+		}
+		if i < lenS {
+			dist[o] = next - i
+		}
+	}
+
+	seenPos := make([]int64, lenS) // pos a state was first seen at, -1 = not yet
+	seenCount := make([]int64, lenS)
+	for i := range seenPos {
+		seenPos[i] = -1
+	}
+	pos, count := int64(0), int64(0)
+	skipped := false
+	for {
+		st := pos % lenS
+		if !skipped && seenPos[st] != -1 {
+			cycleLen := pos - seenPos[st]
+			cycles := (n - pos) / cycleLen
+			pos += cycles * cycleLen
+			count += cycles * (count - seenCount[st])
+			skipped = true // finish the tail one match at a time
+			continue
+		}
+		seenPos[st], seenCount[st] = pos, count
+
+		start := pos + dist[st]
+		if start+lenSub > n {
+			return count
+		}
+		count++
+		pos = start + lenSub
+	}
+}
+
+var (
+	ErrEmptyString = errors.New("repeated string: s is empty")
+	ErrNegativeN   = errors.New("repeated string: n is negative")
+)
+
+// repeatedString for any target rune, counting in runes, with errors for the
+// inputs repeatedString quietly answers 0 for
+func RepeatedStringE(s string, n int64, target rune) (int64, error) {
+	if s == "" {
+		return 0, ErrEmptyString
+	}
+	if n < 0 {
+		return 0, ErrNegativeN
+	}
+	runes := []rune(s)
+	lenS := int64(len(runes))
+	perCopy, inRemainder := int64(0), int64(0)
+	remainder := n % lenS
+	for i, r := range runes {
+		if r == target {
+			perCopy++
+			if int64(i) < remainder {
+				inRemainder++
+			}
+		}
+	}
+	return perCopy*(n/lenS) + inRemainder, nil
+}
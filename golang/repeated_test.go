@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRepeatedCharIndices(t *testing.T) {
+	cases := []struct {
+		s      string
+		n      int64
+		target rune
+		limit  int
+		want   []int64
+	}{
+		{"abca", 7, 'a', 10, []int64{0, 3, 4}}, // n ends inside the second copy
+		{"abca", 10, 'a', 10, []int64{0, 3, 4, 7, 8}},
+		{"abca", 10, 'a', 2, []int64{0, 3}},
+		{"abca", 10, 'z', 10, []int64{}},
+		{"aéa", 5, 'a', 10, []int64{0, 2, 3}}, // rune positions, é is one
+		{"", 5, 'a', 10, []int64{}},
+	}
+	for _, c := range cases {
+		if got := RepeatedCharIndices(c.s, c.n, c.target, c.limit); !slices.Equal(got, c.want) {
+			t.Errorf("RepeatedCharIndices(%q, %d, %q, %d) = %v, want %v", c.s, c.n, c.target, c.limit, got, c.want)
+		}
+	}
+}
+
+func TestRepeatPeriod(t *testing.T) {
+	for s, want := range map[string]int{"abab": 2, "abcac": 5, "aaaa": 1, "a": 1, "": 0, "abcabcab": 8, "abcabc": 3} {
+		if got := RepeatPeriod(s); got != want {
+			t.Errorf("RepeatPeriod(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestRepeatedSubstringCount(t *testing.T) {
+	cases := []struct {
+		s    string
+		n    int64
+		sub  string
+		want int64
+	}{
+		{"abca", 8, "aa", 1},                            // "abcaabca", only across the join
+		{"abca", 12, "aa", 2},                           // joins at 4 and 8
+		{"aba", 10, "ab", 3},                            // "abaabaabaa"
+		{"aaa", 7, "aa", 3},                             // non-overlapping, leftmost first
+		{"abc", 999_999_999_999, "ca", 333_333_333_332}, // every join but past the last copy
+		{"abc", 10, "", 0},
+		{"", 10, "a", 0},
+		{"abc", 2, "abc", 0}, // longer than n
+	}
+	for _, c := range cases {
+		if got := RepeatedSubstringCount(c.s, c.n, c.sub); got != c.want {
+			t.Errorf("RepeatedSubstringCount(%q, %d, %q) = %d, want %d", c.s, c.n, c.sub, got, c.want)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 1000; trial++ {
+		s := randomInput(rng, "ab", 5)
+		sub := randomInput(rng, "ab", 4)
+		if s == "" || sub == "" {
+			continue
+		}
+		n := int64(rng.Intn(40))
+		full := strings.Repeat(s, int(n)/len(s)+1)[:n]
+		if got, want := RepeatedSubstringCount(s, n, sub), int64(strings.Count(full, sub)); got != want {
+			t.Fatalf("RepeatedSubstringCount(%q, %d, %q) = %d, want %d", s, n, sub, got, want)
+		}
+	}
+}
+
+// counts per copy times copies go past int32 for all of these, they'd wrap on a 32 bit int
+func TestRepeatedStringLargeN(t *testing.T) {
+	cases := []struct {
+		s    string
+		n    int64
+		want int64
+	}{
+		{"a", 1_000_000_000_000, 1_000_000_000_000},
+		{strings.Repeat("a", 100), 1_000_000_000_000, 1_000_000_000_000},
+		{"ab", 1_000_000_000_001, 500_000_000_001},
+		{"aba", 10, 7},
+		{"bab", 5_000_000_000, 1_666_666_667}, // 1666666666 copies plus "ba"
+	}
+	for _, c := range cases {
+		if got := repeatedString(c.s, c.n); got != c.want {
+			t.Errorf("repeatedString(%.10q, %d) = %d, want %d", c.s, c.n, got, c.want)
+		}
+	}
+}
+
+func TestRepeatedStringE(t *testing.T) {
+	cases := []struct {
+		s      string
+		n      int64
+		target rune
+		want   int64
+		err    error
+	}{
+		{"aba", 10, 'a', 7, nil},
+		{"aba", 0, 'a', 0, nil},
+		{"héé", 5, 'é', 3, nil}, // runes, not bytes
+		{"", 10, 'a', 0, ErrEmptyString},
+		{"", -1, 'a', 0, ErrEmptyString}, // the empty string is checked first
+		{"aba", -1, 'a', 0, ErrNegativeN},
+	}
+	for _, c := range cases {
+		got, err := RepeatedStringE(c.s, c.n, c.target)
+		if got != c.want || !errors.Is(err, c.err) {
+			t.Errorf("RepeatedStringE(%q, %d, %q) = %d, %v, want %d, %v", c.s, c.n, c.target, got, err, c.want, c.err)
+		}
+	}
+}
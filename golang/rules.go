@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// reads a rules file, one `name = pattern` per line, into compiled patterns by name.
+// blank lines and lines starting with # are skipped. the pattern is everything after
+// the first `=` with the spaces around it trimmed, so it can't start or end with a space.
+// errors carry their line number, a bad pattern wraps its *PatternError
+func LoadRules(r io.Reader) (map[string]*Pattern, error) {
+	rules := map[string]*Pattern{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		name, src, ok := strings.Cut(text, "=")
+		name, src = strings.TrimSpace(name), strings.TrimSpace(src)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("line %d: want `name = pattern`, got %q", line, text)
+		}
+		if _, dup := rules[name]; dup {
+			return nil, fmt.Errorf("line %d: rule %q defined twice", line, name)
+		}
+		p, err := Compile(src)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: rule %q: %w", line, name, err)
+		}
+		rules[name] = p
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
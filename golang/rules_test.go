@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoadRules(t *testing.T) {
+	file := `# log rules
+errors = *.ERROR*.
+
+   # indented comment
+digits=*[0-9]
+`
+	rules, err := LoadRules(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("LoadRules gave %d rules, want 2", len(rules))
+	}
+	if !rules["errors"].Match("xERRORx") || !rules["digits"].Match("2024") || rules["digits"].Match("20x4") {
+		t.Error("loaded rules don't match like their patterns")
+	}
+
+	bad := []struct {
+		file, want string
+	}{
+		{"a = abc\nb = [a-\n", "line 2"},
+		{"a = abc\n\n# x\nno equals\n", "line 4"},
+		{"a = x\na = y\n", "defined twice"},
+		{" = x\n", "line 1"},
+	}
+	for _, c := range bad {
+		_, err := LoadRules(strings.NewReader(c.file))
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("LoadRules(%q) err = %v, want one with %q", c.file, err, c.want)
+		}
+	}
+
+	// the pattern's own offset comes through the line number wrapping
+	_, err = LoadRules(strings.NewReader("ok = a\nbroken = ab{2,1}c\n"))
+	var perr *PatternError
+	if !errors.As(err, &perr) || !strings.HasPrefix(err.Error(), "line 2: ") {
+		t.Fatalf("bad pattern: err = %v, want a *PatternError on line 2", err)
+	}
+	if perr.Pos != 2 {
+		t.Errorf("bad pattern error at offset %d, want 2", perr.Pos)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// solver decodes the positional JSON args for one challenge and runs it
+type solver func(args []json.RawMessage) (any, error)
+
+// algo name -> solver, names are the function names in this package
+var solvers = map[string]solver{
+	"nonDivisibleSubset": func(args []json.RawMessage) (any, error) {
+		var s []int32
+		var k int32
+		if err := decodeArgs(args, &s, &k); err != nil {
+			return nil, err
+		}
+		return nonDivisibleSubset(s, k), nil
+	},
+	"repeatedString": func(args []json.RawMessage) (any, error) {
+		var s string
+		var n int64
+		if err := decodeArgs(args, &s, &n); err != nil {
+			return nil, err
+		}
+		return repeatedString(s, n), nil
+	},
+	"jumpingOnClouds": func(args []json.RawMessage) (any, error) {
+		var c []int32
+		if err := decodeArgs(args, &c); err != nil {
+			return nil, err
+		}
+		return jumpingOnClouds(c), nil
+	},
+	"superReducedString": func(args []json.RawMessage) (any, error) {
+		var s string
+		if err := decodeArgs(args, &s); err != nil {
+			return nil, err
+		}
+		return superReducedString(s), nil
+	},
+	"activityNotifications": func(args []json.RawMessage) (any, error) {
+		var expenditure []int32
+		var d int32
+		if err := decodeArgs(args, &expenditure, &d); err != nil {
+			return nil, err
+		}
+		return activityNotifications(expenditure, d), nil
+	},
+	"regularExpression": func(args []json.RawMessage) (any, error) {
+		var s, r string
+		if err := decodeArgs(args, &s, &r); err != nil {
+			return nil, err
+		}
+		return regularExpression(s, r), nil
+	},
+}
+
+func decodeArgs(args []json.RawMessage, ptrs ...any) error {
+	if len(args) != len(ptrs) {
+		return fmt.Errorf("want %d args, got %d", len(ptrs), len(args))
+	}
+	for i, p := range ptrs {
+		if err := json.Unmarshal(args[i], p); err != nil {
+			return fmt.Errorf("arg %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Solve runs the named challenge with positional JSON args
+func Solve(algo string, args []json.RawMessage) (any, error) {
+	fn, ok := solvers[algo]
+	if !ok {
+		return nil, fmt.Errorf("unknown algo %q", algo)
+	}
+	return fn(args)
+}
+
+// TestCase is one entry of a test file, the file is a JSON array of these:
+//
+//	[{"algo": "repeatedString", "args": ["aba", 10], "expected": 7}]
+//
+// args are the function's parameters in order
+type TestCase struct {
+	Algo     string            `json:"algo"`
+	Args     []json.RawMessage `json:"args"`
+	Expected json.RawMessage   `json:"expected"`
+}
+
+type TestOutcome struct {
+	Case TestCase
+	Got  any
+	Pass bool
+	Err  error // solver could not run (unknown algo, bad args)
+}
+
+// RunTestFile loads a test file and runs every entry, the error is only for
+// a file that can't be read or parsed, failing entries show up in the outcomes
+func RunTestFile(path string) ([]TestOutcome, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cases []TestCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	outcomes := make([]TestOutcome, 0, len(cases))
+	for _, tc := range cases {
+		out := TestOutcome{Case: tc}
+		out.Got, out.Err = Solve(tc.Algo, tc.Args)
+		if out.Err == nil {
+			out.Pass, out.Err = sameJSON(out.Got, tc.Expected)
+		}
+		outcomes = append(outcomes, out)
+	}
+	return outcomes, nil
+}
+
+// compares got and expected after both go through JSON, so 4 == int64(4)
+func sameJSON(got any, expected json.RawMessage) (bool, error) {
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		return false, err
+	}
+	var a, b any
+	if err := json.Unmarshal(gotJSON, &a); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(expected, &b); err != nil {
+		return false, fmt.Errorf("expected: %w", err)
+	}
+	return reflect.DeepEqual(a, b), nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunTestFileSamples(t *testing.T) {
+	outcomes, err := RunTestFile(filepath.Join("testdata", "challenges.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcomes) == 0 {
+		t.Fatal("no entries in testdata/challenges.json")
+	}
+	for i, out := range outcomes {
+		if out.Err != nil || !out.Pass {
+			t.Errorf("entry %d %s%s: got %v, want %s (err %v)", i, out.Case.Algo, out.Case.Args, out.Got, out.Case.Expected, out.Err)
+		}
+	}
+}
+
+func TestRunTestFileFailures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cases.json")
+	data := `[
+		{"algo": "repeatedString", "args": ["aba", 10], "expected": 8},
+		{"algo": "noSuchAlgo", "args": [], "expected": 0},
+		{"algo": "jumpingOnClouds", "args": ["not a slice"], "expected": 0}
+	]`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	outcomes, err := RunTestFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcomes) != 3 {
+		t.Fatalf("%d outcomes, want 3", len(outcomes))
+	}
+	if out := outcomes[0]; out.Pass || out.Err != nil {
+		t.Errorf("wrong expected value: Pass=%v Err=%v, want a plain fail", out.Pass, out.Err)
+	}
+	for _, out := range outcomes[1:] {
+		if out.Pass || out.Err == nil {
+			t.Errorf("%s%s: Pass=%v Err=%v, want an error", out.Case.Algo, out.Case.Args, out.Pass, out.Err)
+		}
+	}
+
+	if _, err := RunTestFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("RunTestFile on a missing file returned no error")
+	}
+}
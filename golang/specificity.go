@@ -0,0 +1,38 @@
+package main
+
+// what each mandatory char is worth to Specificity, a fixed char says the most
+const (
+	specificityLiteral = 3
+	specificityClass   = 2 // a class or \p{name}, some chars
+	specificityAny     = 1 // `.`, any char
+	specificityStar    = 1 // taken off per unbounded repeat, "and maybe more" says less
+)
+
+// Specificity scores how narrowly the pattern picks out strings, for putting rules in
+// order (highest first). each token is worth its weight times the chars it has to take
+// (optional ones add nothing), minus specificityStar if it's unbounded. with
+// alternatives the loosest one counts. `abc` 9 > `a.c` 7 > `...` 3
+func (p *Pattern) Specificity() int {
+	score := 0
+	for a, tokens := range p.alts {
+		alt := 0
+		for i := range tokens {
+			t := &tokens[i]
+			weight := specificityLiteral
+			switch t.kind {
+			case tokAny:
+				weight = specificityAny
+			case tokClass, tokPredicate:
+				weight = specificityClass
+			}
+			alt += weight * t.min
+			if t.max == -1 {
+				alt -= specificityStar
+			}
+		}
+		if a == 0 || alt < score {
+			score = alt
+		}
+	}
+	return score
+}
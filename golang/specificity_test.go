@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestSpecificity(t *testing.T) {
+	cases := []struct {
+		pattern string
+		want    int
+	}{
+		{"abc", 9},
+		{"a[bc]c", 8},
+		{"a.c", 7},
+		{"...", 3},
+		{"ab?", 3},    // the optional b adds nothing
+		{"*ab", 5},    // one a, minus a star
+		{"{2,}a", 5},  // two a's, minus a star
+		{"{2,4}a", 6}, // bounded, only the two it has to take
+		{"abc|.", 1},  // the loosest alternative
+		{"", 0},
+	}
+	for _, c := range cases {
+		if got := MustCompile(c.pattern).Specificity(); got != c.want {
+			t.Errorf("%q.Specificity() = %d, want %d", c.pattern, got, c.want)
+		}
+	}
+
+	order := []string{"abc", "a.c", "*a.c", "...", "*."} // most specific first
+	for i := 1; i < len(order); i++ {
+		if a, b := MustCompile(order[i-1]).Specificity(), MustCompile(order[i]).Specificity(); a <= b {
+			t.Errorf("%q (%d) isn't more specific than %q (%d)", order[i-1], a, order[i], b)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// ToStdRegexp writes the pattern out in regexp syntax and compiles it, anchored at both
+// ends like Match and with `.` taking newlines too, so MatchString agrees with Match.
+// named groups become (?P<name>...) groups.
+// predicates, possessive repeats and byte mode have no regexp equivalent and are errors
+func (p *Pattern) ToStdRegexp() (*regexp.Regexp, error) {
+	if p.byteMode {
+		return nil, errors.New("ToStdRegexp: byte mode has no regexp equivalent")
+	}
+	alts := []string{}
+	for _, tokens := range p.alts {
+		var sb strings.Builder
+		for i := range tokens {
+			t := &tokens[i]
+			if t.group != "" && (i == 0 || tokens[i-1].group != t.group) {
+				sb.WriteString("(?P<" + t.group + ">")
+			}
+			switch {
+			case t.possessive:
+				return nil, fmt.Errorf("ToStdRegexp: possessive repeat at offset %d", t.pos)
+			case t.kind == tokPredicate:
+				return nil, fmt.Errorf("ToStdRegexp: predicate %q at offset %d", t.name, t.pos)
+			case t.kind == tokAny:
+				sb.WriteByte('.')
+			case t.kind == tokClass:
+				sb.WriteByte('[')
+				if t.class.negate {
+					sb.WriteByte('^')
+				}
+				for _, rg := range t.class.ranges {
+					fmt.Fprintf(&sb, `\x{%x}`, rg[0])
+					if rg[1] != rg[0] {
+						fmt.Fprintf(&sb, `-\x{%x}`, rg[1])
+					}
+				}
+				sb.WriteByte(']')
+			default:
+				sb.WriteString(regexp.QuoteMeta(string(t.r)))
+			}
+			sb.WriteString(stdRepeat(t.min, t.max))
+			if t.group != "" && (i == len(tokens)-1 || tokens[i+1].group != t.group) {
+				sb.WriteByte(')')
+			}
+		}
+		alts = append(alts, sb.String())
+	}
+	return regexp.Compile(`(?s)^(?:` + strings.Join(alts, "|") + `)$`)
+}
+
+// regexp's suffix for min..max repeats of one atom
+func stdRepeat(min, max int) string {
+	switch {
+	case min == 1 && max == 1:
+		return ""
+	case min == 0 && max == 1:
+		return "?"
+	case min == 0 && max == -1:
+		return "*"
+	case min == 1 && max == -1:
+		return "+"
+	case max == -1:
+		return fmt.Sprintf("{%d,}", min)
+	case min == max:
+		return fmt.Sprintf("{%d}", min)
+	default:
+		return fmt.Sprintf("{%d,%d}", min, max)
+	}
+}
+
+// ErrUnsupportedSyntax is what FromStdRegexp wraps for regexp features the dialect lacks
+var ErrUnsupportedSyntax = errors.New("unsupported regexp syntax")
+
+// FromStdRegexp turns a simple regexp into the dialect: literals, `.`, classes, the
+// repeats `+ * ? {m,n}` on a single char, `|`, and `^` / `$` at the ends (matching is
+// anchored anyway, with or without them). groups, `\d` style classes, flags and
+// anything else is ErrUnsupportedSyntax. `.` doesn't take a newline in regexp, so it
+// becomes `[^\n]`. `a+bc.` -> `*abc[^\n]`
+func FromStdRegexp(src string) (*Pattern, error) {
+	// regexp.Compile's flags minus the Perl extras, so `\d`, `(?:` and co fail here
+	re, err := syntax.Parse(src, syntax.OneLine|syntax.ClassNL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedSyntax, err)
+	}
+	tops := []*syntax.Regexp{re}
+	if re.Op == syntax.OpAlternate {
+		tops = re.Sub
+	}
+	alts := []string{}
+	for _, top := range tops {
+		seqs, err := stdToDialect(stripAnchors(top))
+		if err != nil {
+			return nil, err
+		}
+		for _, seq := range seqs {
+			if len(seq) == 0 {
+				return nil, fmt.Errorf("%w: empty alternative", ErrUnsupportedSyntax)
+			}
+			alts = append(alts, strings.Join(seq, ""))
+		}
+	}
+	return Compile(strings.Join(alts, "|"))
+}
+
+// drops a leading `^` and trailing `$`, anywhere else they're unsupported
+func stripAnchors(re *syntax.Regexp) *syntax.Regexp {
+	switch {
+	case re.Op == syntax.OpBeginText || re.Op == syntax.OpEndText:
+		return &syntax.Regexp{Op: syntax.OpConcat}
+	case re.Op != syntax.OpConcat:
+		return re
+	}
+	subs := re.Sub
+	if len(subs) > 0 && subs[0].Op == syntax.OpBeginText {
+		subs = subs[1:]
+	}
+	if len(subs) > 0 && subs[len(subs)-1].Op == syntax.OpEndText {
+		subs = subs[:len(subs)-1]
+	}
+	return &syntax.Regexp{Op: syntax.OpConcat, Sub: subs}
+}
+
+// the dialect spelling of re as alternatives, each a list of atoms (with their repeat).
+// the parser factors `abc|abd` into ab(c|d), so an alternation can turn up inside a
+// concat, it's multiplied back out since the dialect only has `|` at the top
+func stdToDialect(re *syntax.Regexp) ([][]string, error) {
+	switch re.Op {
+	case syntax.OpConcat:
+		seqs := [][]string{{}}
+		for _, sub := range re.Sub {
+			subSeqs, err := stdToDialect(sub)
+			if err != nil {
+				return nil, err
+			}
+			next := [][]string{}
+			for _, seq := range seqs {
+				for _, tail := range subSeqs {
+					next = append(next, append(append([]string{}, seq...), tail...))
+				}
+			}
+			seqs = next
+		}
+		return seqs, nil
+	case syntax.OpAlternate:
+		seqs := [][]string{}
+		for _, sub := range re.Sub {
+			subSeqs, err := stdToDialect(sub)
+			if err != nil {
+				return nil, err
+			}
+			seqs = append(seqs, subSeqs...)
+		}
+		return seqs, nil
+	case syntax.OpEmptyMatch:
+		return [][]string{{}}, nil // the nothing in a factored `ab|a`
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			break
+		}
+		seq := []string{}
+		for _, r := range re.Rune {
+			var sb strings.Builder
+			writeLiteral(&sb, r)
+			seq = append(seq, sb.String())
+		}
+		return [][]string{seq}, nil
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		atom, ok := stdAtom(re.Sub[0])
+		if !ok {
+			break
+		}
+		min, max := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			min, max = 0, -1
+		case syntax.OpPlus:
+			min, max = 1, -1
+		case syntax.OpQuest:
+			return [][]string{{atom + "?"}}, nil
+		}
+		switch {
+		case max == 0:
+			break // x{0} matches nothing at all, the dialect has no way to say that
+		case min == 1 && max == -1:
+			return [][]string{{"*" + atom}}, nil
+		case min == max:
+			return [][]string{{fmt.Sprintf("{%d}%s", min, atom)}}, nil
+		case max == -1:
+			return [][]string{{fmt.Sprintf("{%d,}%s", min, atom)}}, nil
+		default:
+			return [][]string{{fmt.Sprintf("{%d,%d}%s", min, max, atom)}}, nil
+		}
+	default:
+		if atom, ok := stdAtom(re); ok {
+			return [][]string{{atom}}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedSyntax, re)
+}
+
+// a single char regexp as one dialect atom
+func stdAtom(re *syntax.Regexp) (string, bool) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		if len(re.Rune) != 1 || re.Flags&syntax.FoldCase != 0 {
+			return "", false
+		}
+		var sb strings.Builder
+		writeLiteral(&sb, re.Rune[0])
+		return sb.String(), true
+	case syntax.OpAnyChar:
+		return ".", true
+	case syntax.OpAnyCharNotNL:
+		return "[^\n]", true
+	case syntax.OpCharClass:
+		return stdClass(re.Rune)
+	}
+	return "", false
+}
+
+// regexp's class (lo, hi pairs) in the dialect's `[...]`. the parser keeps classes
+// positive and sorted, a negated one comes out as the ranges around it.
+// a one char class is written as that literal
+func stdClass(pairs []rune) (string, bool) {
+	if len(pairs) == 2 && pairs[0] == pairs[1] {
+		var sb strings.Builder
+		writeLiteral(&sb, pairs[0])
+		return sb.String(), true
+	}
+	// anything starting with `-` goes first, after a char `+--/` would read as +..- then /.
+	// `^` can't lead or it negates, so it's moved back if it would
+	first, rest := [][2]rune{}, [][2]rune{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		rg := [2]rune{pairs[i], pairs[i+1]}
+		switch {
+		case rg[0] == ']' || rg[1] == ']':
+			return "", false // `]` always closes a class, it can't be a range edge
+		case rg[0] == '-':
+			first = append(first, rg)
+		default:
+			rest = append(rest, rg)
+		}
+	}
+	ranges := append(first, rest...)
+	if lead := ranges[0]; lead[0] == '^' {
+		if len(ranges) > 1 {
+			ranges = append(ranges[1:], lead)
+		} else {
+			ranges = [][2]rune{{'^' + 1, lead[1]}, {'^', '^'}} // ^..x on its own, split off the ^
+		}
+	}
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for _, rg := range ranges {
+		sb.WriteRune(rg[0])
+		if rg[1] != rg[0] {
+			sb.WriteByte('-')
+			sb.WriteRune(rg[1])
+		}
+	}
+	sb.WriteByte(']')
+	src := sb.String()
+
+	// read it back to be sure the spelling didn't change what's in it
+	back, _, err := parseClass(src, 0, utf8.DecodeRuneInString)
+	want := &charClass{ranges: ranges}
+	if err != nil || back.negate || !slices.Equal(back.merged(), want.merged()) {
+		return "", false
+	}
+	return src, true
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"regexp"
+	"testing"
+)
+
+func TestToStdRegexp(t *testing.T) {
+	for _, c := range matchCases {
+		re, err := MustCompile(c.pattern).ToStdRegexp()
+		if err != nil {
+			t.Errorf("ToStdRegexp(%q): %v", c.pattern, err)
+			continue
+		}
+		if got := re.MatchString(c.s); got != c.want {
+			t.Errorf("ToStdRegexp(%q) = %s, MatchString(%q) = %v, want %v", c.pattern, re, c.s, got, c.want)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(12))
+	for trial := 0; trial < 500; trial++ {
+		p := MustCompile(randomPattern(rng, false))
+		re, err := p.ToStdRegexp()
+		if err != nil {
+			t.Fatalf("ToStdRegexp(%q): %v", p.src, err)
+		}
+		for k := 0; k < 20; k++ {
+			s := randomInput(rng, "ab\n", 6) // `.` takes the newline in both
+			if got, want := re.MatchString(s), p.Match(s); got != want {
+				t.Fatalf("%q as %s on %q: regexp says %v, Match %v", p.src, re, s, got, want)
+			}
+		}
+	}
+
+	for _, src := range []string{"*+a", `\p{x}`} {
+		p := MustCompile(src, WithPredicates(map[string]func(rune) bool{"x": func(rune) bool { return true }}))
+		if _, err := p.ToStdRegexp(); err == nil {
+			t.Errorf("ToStdRegexp(%q): no error", src)
+		}
+	}
+	if _, err := MustCompile("ab", WithByteMode()).ToStdRegexp(); err == nil {
+		t.Error("ToStdRegexp in byte mode: no error")
+	}
+}
+
+func TestFromStdRegexp(t *testing.T) {
+	good := []struct{ re, src string }{
+		{"a+bc.", "*abc[^\n]"},
+		{"^a*b$", "{0,}ab"},
+		{"abc|abd", "ab[c-d]"}, // the parser factors it
+		{"x{2,3}y?", "{2,3}xy?"},
+	}
+	for _, c := range good {
+		p, err := FromStdRegexp(c.re)
+		if err != nil || p.src != c.src {
+			t.Errorf("FromStdRegexp(%q) = %v, %v, want %q", c.re, p, err, c.src)
+		}
+	}
+
+	for _, re := range []string{"(ab)+", `\d`, "(?i)a", "a^b", "x{0}"} {
+		if _, err := FromStdRegexp(re); !errors.Is(err, ErrUnsupportedSyntax) {
+			t.Errorf("FromStdRegexp(%q) err = %v, want ErrUnsupportedSyntax", re, err)
+		}
+	}
+
+	// a literal + has to stay a literal, not turn the repeat before it possessive
+	plus := []struct {
+		re     string
+		inputs []string
+	}{
+		{`\++a`, []string{"+a", "++a", "a", "+", "++"}},
+		{`a\+{2}b`, []string{"a++b", "a+b", "aab", "a+++b", "ab"}},
+		{`\++`, []string{"+", "+++", "", "a"}},
+	}
+	for _, c := range plus {
+		p, err := FromStdRegexp(c.re)
+		if err != nil {
+			t.Errorf("FromStdRegexp(%q): %v", c.re, err)
+			continue
+		}
+		re := regexp.MustCompile("^(?:" + c.re + ")$")
+		for _, s := range c.inputs {
+			if got, want := p.Match(s), re.MatchString(s); got != want {
+				t.Errorf("FromStdRegexp(%q) = %q on %q: %v, regexp says %v", c.re, p.src, s, got, want)
+			}
+		}
+	}
+
+	// same answers as regexp itself, anchored like Match
+	sources := []string{"a+bc.", "^a*b$", "abc|abd", "x{2,3}y?", "[a-c]+|b?c", "a|ab|abc", "[^a]{1,2}b*"}
+	rng := rand.New(rand.NewSource(13))
+	for _, src := range sources {
+		p, err := FromStdRegexp(src)
+		if err != nil {
+			t.Fatalf("FromStdRegexp(%q): %v", src, err)
+		}
+		re := regexp.MustCompile("^(?:" + src + ")$")
+		for k := 0; k < 200; k++ {
+			s := randomInput(rng, "abcxy\n", 6)
+			if got, want := p.Match(s), re.MatchString(s); got != want {
+				t.Fatalf("FromStdRegexp(%q) = %q on %q: %v, regexp says %v", src, p.src, s, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+// brute force O(n^2) count of pairs i < j where pred(s[i], s[j]),
+// the slow but obviously right reference for the bucketed pair checks
+func CountPairs[T any](s []T, pred func(a, b T) bool) int {
+	count := 0
+	for i := range s {
+		for j := i + 1; j < len(s); j++ {
+			if pred(s[i], s[j]) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// items with repeats dropped, keeping the first occurrence of each in order
+func Deduplicate[T comparable](items []T) []T {
+	seen := make(map[T]bool, len(items))
+	res := []T{}
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			res = append(res, item)
+		}
+	}
+	return res
+}
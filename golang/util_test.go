@@ -0,0 +1,44 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestCountPairs(t *testing.T) {
+	sumsToTen := func(a, b int) bool { return a+b == 10 }
+	if got := CountPairs([]int{1, 9, 5, 5, 5, 3}, sumsToTen); got != 4 { // 1+9 and three 5+5
+		t.Errorf("CountPairs = %d, want 4", got)
+	}
+	if got := CountPairs([]int{}, sumsToTen); got != 0 {
+		t.Errorf("CountPairs on nothing = %d, want 0", got)
+	}
+}
+
+func TestHasPairDivisibleByAgainstCountPairs(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 2000; trial++ {
+		s := randomSmallSet(rng, rng.Intn(8))
+		k := int32(1 + rng.Intn(9))
+		want := CountPairs(s, func(a, b int32) bool { return (a+b)%k == 0 }) > 0
+		if got := HasPairDivisibleBy(s, k); got != want {
+			t.Fatalf("HasPairDivisibleBy(%v, %d) = %v, CountPairs says %v", s, k, got, want)
+		}
+	}
+}
+
+func TestDeduplicate(t *testing.T) {
+	if got := Deduplicate([]int{3, 1, 3, 2, 1, 3}); !slices.Equal(got, []int{3, 1, 2}) {
+		t.Errorf("Deduplicate ints = %v, want [3 1 2]", got)
+	}
+	if got := Deduplicate([]string{"b", "a", "b", "c", "a"}); !slices.Equal(got, []string{"b", "a", "c"}) {
+		t.Errorf("Deduplicate strings = %q, want [b a c]", got)
+	}
+	if got := Deduplicate([]string{"x", "x", "x"}); !slices.Equal(got, []string{"x"}) {
+		t.Errorf("Deduplicate all duplicates = %q, want [x]", got)
+	}
+	if got := Deduplicate([]int(nil)); got == nil || len(got) != 0 {
+		t.Errorf("Deduplicate(nil) = %#v, want an empty slice", got)
+	}
+}